@@ -6,21 +6,31 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/collectors"
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/enrichment"
 	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/pflag"
+	"github.com/spotinst/spotinst-sdk-go/service/elastigroup"
+	"github.com/spotinst/spotinst-sdk-go/service/managedinstance"
 	"github.com/spotinst/spotinst-sdk-go/service/mcs"
 	"github.com/spotinst/spotinst-sdk-go/service/ocean"
 	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/gcp"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/spark"
+	"github.com/spotinst/spotinst-sdk-go/service/oceancd"
+	"github.com/spotinst/spotinst-sdk-go/spotinst/client"
 	"github.com/spotinst/spotinst-sdk-go/spotinst/session"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
 )
 
 var logger logr.Logger
@@ -45,17 +55,113 @@ func main() {
 		"resource-labels",
 		"Comma-separated list of Kubernetes resource labels (with optional Prometheus label mapping) to propagate onto metrics. E.g. 'mylabel,otherresourcelabel=someprometheuslabel'",
 	)
+
+	var labelFallbacks labels.Sources
+	pflag.Var(
+		&labelFallbacks,
+		"resource-label-fallbacks",
+		"Comma-separated list of label sources to fall back to, in order, if a workload or namespace does not carry a mapped label. Valid sources are 'namespace' (namespace labels) and 'cluster' (Ocean cluster tags).",
+	)
+	exposeLabelSources := pflag.Bool(
+		"expose-resource-label-sources",
+		false,
+		"Add a '<label>_source' label for every mapped resource label which contains the source the value was resolved from. Useful for debugging label fallbacks.",
+	)
+
+	var clusterTagMappings labels.Mappings
+	pflag.Var(
+		&clusterTagMappings,
+		"cluster-tags",
+		"Comma-separated list of Ocean cluster tags (with optional Prometheus label mapping) to propagate onto cluster-level metrics. E.g. 'team,cost-center=cost_center'",
+	)
+
+	var constLabels labels.ConstLabels
+	pflag.Var(
+		&constLabels,
+		"const-labels",
+		"Comma-separated list of static labels to attach to every metric. E.g. 'environment=prod,region=eu-west-1'. Metrics which carry a label of the same name keep their own value.",
+	)
+
+	kubeconfig := pflag.String(
+		"kubeconfig",
+		"",
+		"Path to a kubeconfig for enriching metrics with Kubernetes labels and annotations. If empty, $KUBECONFIG, ~/.kube/config and the in-cluster config are tried. Enrichment is disabled if none is available.",
+	)
+	kubernetesClusterID := pflag.String(
+		"kubernetes-cluster-id",
+		"",
+		"The Ocean controller cluster ID of the Kubernetes cluster used for enrichment. Discovered from the Ocean controller config if empty.",
+	)
+	rollupWorkloads := pflag.Bool(
+		"rollup-workloads",
+		false,
+		"Roll up Jobs into their owning CronJobs and ReplicaSets into their owning Deployments. Owners are looked up via Kubernetes owner references if enrichment is enabled, and inferred from the workload names otherwise.",
+	)
+	legacyResourceSuggestions := pflag.Bool(
+		"legacy-resource-suggestions",
+		false,
+		"Use the legacy resource suggestions endpoint instead of the Ocean right-sizing recommendations API.",
+	)
+	resourceSuggestionsNamespaces := pflag.StringSlice(
+		"resource-suggestions-namespaces",
+		nil,
+		"Comma-separated list of namespaces to fetch resource suggestions for. Defaults to all namespaces.",
+	)
+	resourceSuggestionsLabel := pflag.String(
+		"resource-suggestions-label",
+		"",
+		"Only fetch resource suggestions for workloads with this label, in the format 'key=value'. Not supported with --legacy-resource-suggestions.",
+	)
+	resourceSuggestionsWorkloads := pflag.StringSlice(
+		"resource-suggestions-workloads",
+		nil,
+		"Comma-separated list of workload kinds to export resource suggestions for, e.g. 'deployment,statefulset'. Defaults to all workload kinds.",
+	)
+	resourceSuggestionsTolerance := pflag.Float64(
+		"resource-suggestions-tolerance",
+		0.1,
+		"Over-provisioning ratio up to which a workload's requests are considered to match the suggestion, e.g. 0.1 for +/-10%.",
+	)
+	cacheTTL := pflag.Duration(
+		"cache-ttl",
+		30*time.Second,
+		"How long responses of the Spotinst API are shared between the collectors. Should be shorter than the scrape interval.",
+	)
+	sparkApplicationsLookback := pflag.Duration(
+		"spark-applications-lookback",
+		24*time.Hour,
+		"How far back Ocean for Apache Spark applications are exported, based on the time they were created.",
+	)
 	pflag.Parse()
 
-	logger.Info("propagating resource labels", "mapping", labelMappings)
+	logger.Info("propagating resource labels", "mapping", labelMappings, "fallbacks", labelFallbacks)
+
+	labelResolver := labels.NewResolver(labelMappings, labelFallbacks, *exposeLabelSources)
+
+	labelKey, labelValue, _ := strings.Cut(*resourceSuggestionsLabel, "=")
+	suggestionsOptions := collectors.ResourceSuggestionsOptions{
+		Legacy:     *legacyResourceSuggestions,
+		Namespaces: *resourceSuggestionsNamespaces,
+		LabelKey:   labelKey,
+		LabelValue: labelValue,
+		Workloads:  *resourceSuggestionsWorkloads,
+		Tolerance:  *resourceSuggestionsTolerance,
+	}
+
+	if err := suggestionsOptions.Validate(); err != nil {
+		logger.Error(err, "invalid resource suggestions options")
+		os.Exit(1)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	go handleSignals(cancel)
 
 	sess := session.New()
 	mcsClient := mcs.New(sess)
+	apiClient := client.New(sess.Config)
 
 	oceanAWSClient := ocean.New(sess).CloudProviderAWS()
+	cachingClient := collectors.NewCachingClient(mcsClient, oceanAWSClient, *cacheTTL)
 
 	clusters, err := getOceanAWSClusters(ctx, oceanAWSClient)
 	if err != nil {
@@ -63,9 +169,57 @@ func main() {
 		os.Exit(1)
 	}
 
+	oceanGCPClient := ocean.New(sess).CloudProviderGCP()
+
+	// Most accounts only use a single cloud provider, so failing to list the
+	// GCP or Azure clusters only disables the collectors of that provider.
+	gcpClusters, err := getOceanGCPClusters(ctx, oceanGCPClient)
+	if err != nil {
+		logger.Error(err, "failed to fetch ocean gcp clusters, ocean gcp metrics disabled")
+	}
+
+	oceanAzureClient := ocean.New(sess).CloudProviderAzureNP()
+
+	azureClusters, err := getOceanAzureClusters(ctx, oceanAzureClient)
+	if err != nil {
+		logger.Error(err, "failed to fetch ocean azure clusters, ocean azure metrics disabled")
+	}
+
+	elastigroupAWSClient := elastigroup.New(sess).CloudProviderAWS()
+	elastigroupGCPClient := elastigroup.New(sess).CloudProviderGCP()
+	elastigroupAzureClient := elastigroup.New(sess).CloudProviderAzureV3()
+	statefulNodeAWSClient := managedinstance.New(sess).CloudProviderAWS()
+	oceanSparkClient := spark.New(sess)
+	oceanCDClient := oceancd.New(sess)
+
+	metadata := setupKubernetesEnrichment(ctx, *kubeconfig, *kubernetesClusterID)
+
 	registry := prometheus.NewRegistry()
-	registry.MustRegister(collectors.NewOceanAWSClusterCostsCollector(ctx, logger, mcsClient, clusters, labelMappings))
-	registry.MustRegister(collectors.NewOceanAWSResourceSuggestionsCollector(ctx, logger, oceanAWSClient, clusters))
+
+	mustRegister(
+		registry,
+		constLabels,
+		collectors.NewOceanAWSClusterCostsCollector(ctx, logger, cachingClient, clusters, labelResolver, clusterTagMappings, metadata, *rollupWorkloads),
+		collectors.NewOceanAWSResourceSuggestionsCollector(ctx, logger, cachingClient, clusters, labelResolver, metadata, *rollupWorkloads, suggestionsOptions),
+		collectors.NewOceanAWSRightsizingSavingsCollector(ctx, logger, cachingClient, cachingClient, clusters, labelResolver, clusterTagMappings, metadata, *rollupWorkloads, suggestionsOptions),
+		collectors.NewOceanAWSClusterNodesCollector(ctx, logger, cachingClient, clusters),
+		collectors.NewOceanAWSLaunchSpecsCollector(ctx, logger, cachingClient, cachingClient, clusters),
+		collectors.NewOceanAWSClusterInfoCollector(ctx, logger, cachingClient, clusterTagMappings),
+		collectors.NewOceanAWSInstanceMixCollector(ctx, logger, cachingClient, cachingClient, cachingClient, clusters),
+		collectors.NewOceanAWSAutoscalerEventsCollector(ctx, logger, oceanAWSClient, clusters),
+		collectors.NewOceanAWSRollsCollector(ctx, logger, oceanAWSClient, clusters),
+		collectors.NewOceanAWSHeadroomCollector(ctx, logger, cachingClient, cachingClient, oceanAWSClient, clusters),
+		collectors.NewOceanGCPClusterCostsCollector(ctx, logger, cachingClient, gcpClusters, labelResolver, clusterTagMappings, metadata, *rollupWorkloads),
+		collectors.NewOceanAzureClusterCostsCollector(ctx, logger, cachingClient, azureClusters, labelResolver, clusterTagMappings, metadata, *rollupWorkloads),
+		collectors.NewOceanAzureClusterInfoCollector(ctx, logger, oceanAzureClient, clusterTagMappings),
+		collectors.NewOceanAzureVirtualNodeGroupsCollector(ctx, logger, oceanAzureClient, azureClusters),
+		collectors.NewElastigroupAWSCollector(ctx, logger, elastigroupAWSClient, clusterTagMappings),
+		collectors.NewElastigroupGCPCollector(ctx, logger, elastigroupGCPClient, clusterTagMappings),
+		collectors.NewElastigroupAzureCollector(ctx, logger, elastigroupAzureClient, clusterTagMappings),
+		collectors.NewStatefulNodeAWSCollector(ctx, logger, statefulNodeAWSClient),
+		collectors.NewOceanSparkCollector(ctx, logger, oceanSparkClient, apiClient, *sparkApplicationsLookback),
+		collectors.NewOceanCDCollector(ctx, logger, oceanCDClient, apiClient),
+	)
 
 	handler := http.NewServeMux()
 	handler.HandleFunc("/healthz", healthzHandler)
@@ -74,6 +228,26 @@ func main() {
 	listenAndServe(ctx, handler, *addr)
 }
 
+// mustRegister registers the collectors with the registry and attaches the
+// constant labels to their metrics. Constant labels are left out on collectors
+// which attach a label of the same name themselves, e.g. the region of
+// spotinst_ocean_aws_cluster_info.
+func mustRegister(registry *prometheus.Registry, constLabels labels.ConstLabels, cs ...prometheus.Collector) {
+	for _, collector := range cs {
+		applicable := collectors.ApplicableConstLabels(collector, prometheus.Labels(constLabels))
+		if len(applicable) < len(constLabels) {
+			logger.Info(
+				"not attaching constant labels which are already used by the metrics of a collector",
+				"collector", fmt.Sprintf("%T", collector),
+				"labels", constLabels,
+				"applicable", applicable,
+			)
+		}
+
+		prometheus.WrapRegistererWith(applicable, registry).MustRegister(collector)
+	}
+}
+
 func handleSignals(cancelFunc func()) {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
@@ -110,6 +284,41 @@ func listenAndServe(ctx context.Context, handler http.Handler, addr string) {
 	}
 }
 
+// setupKubernetesEnrichment starts watching the namespaces and workloads of the
+// Kubernetes cluster if a client config is available. Returns nil if
+// enrichment is disabled.
+func setupKubernetesEnrichment(ctx context.Context, kubeconfig, clusterID string) collectors.KubernetesMetadataProvider {
+	config, err := enrichment.LoadConfig(kubeconfig)
+	if err != nil {
+		logger.Info("no kubernetes config available, kubernetes enrichment disabled", "reason", err.Error())
+		return nil
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		logger.Error(err, "failed to create kubernetes client")
+		os.Exit(1)
+	}
+
+	if clusterID == "" {
+		clusterID, err = enrichment.DiscoverClusterID(ctx, client)
+		if err != nil {
+			logger.Error(err, "failed to discover ocean controller cluster ID, kubernetes enrichment disabled")
+			return nil
+		}
+	}
+
+	store := enrichment.NewStore(client, clusterID, 10*time.Minute)
+	if err := store.Start(ctx, time.Minute); err != nil {
+		logger.Error(err, "failed to sync kubernetes caches, kubernetes enrichment disabled")
+		return nil
+	}
+
+	logger.Info("kubernetes enrichment enabled", "cluster_id", clusterID)
+
+	return store
+}
+
 func getOceanAWSClusters(ctx context.Context, client aws.Service) ([]*aws.Cluster, error) {
 	output, err := client.ListClusters(ctx, &aws.ListClustersInput{})
 	if err != nil {
@@ -119,6 +328,24 @@ func getOceanAWSClusters(ctx context.Context, client aws.Service) ([]*aws.Cluste
 	return output.Clusters, nil
 }
 
+func getOceanGCPClusters(ctx context.Context, client gcp.Service) ([]*gcp.Cluster, error) {
+	output, err := client.ListClusters(ctx, &gcp.ListClustersInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Clusters, nil
+}
+
+func getOceanAzureClusters(ctx context.Context, client azure.Service) ([]*azure.Cluster, error) {
+	output, err := client.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Clusters, nil
+}
+
 func healthzHandler(w http.ResponseWriter, r *http.Request) {
 	if _, err := w.Write([]byte("ok")); err != nil {
 		logger.Error(err, "failed to write health check status")
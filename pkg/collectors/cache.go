@@ -0,0 +1,179 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/spotinst/spotinst-sdk-go/service/mcs"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+)
+
+// responseCache caches responses of the Spotinst API for a limited time.
+// Concurrent lookups of the same key wait for the request of the first
+// lookup instead of sending their own. Errors are not cached.
+type responseCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	done    chan struct{}
+	expires time.Time
+	value   any
+	err     error
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// cached returns the cached response for the key, or calls fetch and caches
+// its response if there is none.
+func cached[T any](c *responseCache, key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+
+	now := c.now()
+	c.prune(now)
+
+	entry, ok := c.entries[key]
+	if ok {
+		c.mu.Unlock()
+		<-entry.done
+
+		value, _ := entry.value.(T)
+
+		return value, entry.err
+	}
+
+	entry = &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	value, err := fetch()
+
+	entry.value, entry.err, entry.expires = value, err, c.now().Add(c.ttl)
+	close(entry.done)
+
+	return value, err
+}
+
+// prune removes the entries which are expired or failed. Must be called with
+// the lock held.
+func (c *responseCache) prune(now time.Time) {
+	for key, entry := range c.entries {
+		select {
+		case <-entry.done:
+			if entry.err != nil || !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		default:
+			// The request is still in flight.
+		}
+	}
+}
+
+// cacheKey returns the key of a request to the Spotinst API, which is made
+// up of the name of the method and its input.
+func cacheKey(method string, input any) string {
+	// The inputs of the Spotinst SDK are plain structs, which can always be
+	// encoded.
+	encoded, _ := json.Marshal(input)
+
+	return method + ":" + string(encoded)
+}
+
+// CachedOceanAWSClient is the interface of the Ocean AWS clients whose
+// responses are cached by CachingClient.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client.
+type CachedOceanAWSClient interface {
+	OceanAWSClustersClient
+	OceanAWSResourceSuggestionsClient
+	OceanAWSClusterNodesClient
+	OceanAWSLaunchSpecsClient
+}
+
+// CachingClient wraps the Spotinst clients whose responses are used by
+// multiple collectors and caches the responses for a short time. Collectors
+// which are collected by the same scrape thereby share a single request to
+// the Spotinst API instead of sending one each, e.g. the cluster nodes are
+// read once per scrape instead of once per collector.
+//
+// It implements the OceanClusterCostsClient interface and the interfaces
+// embedded in CachedOceanAWSClient.
+type CachingClient struct {
+	costsClient OceanClusterCostsClient
+	oceanClient CachedOceanAWSClient
+	cache       *responseCache
+}
+
+// NewCachingClient creates a new CachingClient which caches the responses of
+// the wrapped clients for ttl. The ttl should be shorter than the scrape
+// interval, so that every scrape fetches fresh data.
+func NewCachingClient(
+	costsClient OceanClusterCostsClient,
+	oceanClient CachedOceanAWSClient,
+	ttl time.Duration,
+) *CachingClient {
+	return &CachingClient{
+		costsClient: costsClient,
+		oceanClient: oceanClient,
+		cache:       newResponseCache(ttl),
+	}
+}
+
+// GetClusterCosts implements OceanClusterCostsClient.
+func (c *CachingClient) GetClusterCosts(ctx context.Context, input *mcs.ClusterCostInput) (*mcs.ClusterCostOutput, error) {
+	return cached(c.cache, cacheKey("GetClusterCosts", input), func() (*mcs.ClusterCostOutput, error) {
+		return c.costsClient.GetClusterCosts(ctx, input)
+	})
+}
+
+// ListOceanResourceSuggestions implements OceanAWSResourceSuggestionsClient.
+func (c *CachingClient) ListOceanResourceSuggestions(
+	ctx context.Context,
+	input *aws.ListOceanResourceSuggestionsInput,
+) (*aws.ListOceanResourceSuggestionsOutput, error) {
+	return cached(c.cache, cacheKey("ListOceanResourceSuggestions", input), func() (*aws.ListOceanResourceSuggestionsOutput, error) {
+		return c.oceanClient.ListOceanResourceSuggestions(ctx, input)
+	})
+}
+
+// GetRightsizingRecommendations implements OceanAWSResourceSuggestionsClient.
+func (c *CachingClient) GetRightsizingRecommendations(
+	ctx context.Context,
+	input *aws.GetRightsizingRecommendationsInput,
+) (*aws.GetRightsizingRecommendationsOutput, error) {
+	return cached(c.cache, cacheKey("GetRightsizingRecommendations", input), func() (*aws.GetRightsizingRecommendationsOutput, error) {
+		return c.oceanClient.GetRightsizingRecommendations(ctx, input)
+	})
+}
+
+// ListClusters implements OceanAWSClustersClient.
+func (c *CachingClient) ListClusters(ctx context.Context, input *aws.ListClustersInput) (*aws.ListClustersOutput, error) {
+	return cached(c.cache, cacheKey("ListClusters", input), func() (*aws.ListClustersOutput, error) {
+		return c.oceanClient.ListClusters(ctx, input)
+	})
+}
+
+// ReadClusterNodes implements OceanAWSClusterNodesClient.
+func (c *CachingClient) ReadClusterNodes(ctx context.Context, input *aws.ReadClusterNodeInput) (*aws.ReadClusterNodeOutput, error) {
+	return cached(c.cache, cacheKey("ReadClusterNodes", input), func() (*aws.ReadClusterNodeOutput, error) {
+		return c.oceanClient.ReadClusterNodes(ctx, input)
+	})
+}
+
+// ListLaunchSpecs implements OceanAWSLaunchSpecsClient.
+func (c *CachingClient) ListLaunchSpecs(ctx context.Context, input *aws.ListLaunchSpecsInput) (*aws.ListLaunchSpecsOutput, error) {
+	return cached(c.cache, cacheKey("ListLaunchSpecs", input), func() (*aws.ListLaunchSpecsOutput, error) {
+		return c.oceanClient.ListLaunchSpecs(ctx, input)
+	})
+}
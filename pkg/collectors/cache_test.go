@@ -0,0 +1,149 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResponseCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := newResponseCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	var calls int
+
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	value, err := cached(cache, "foo", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	// Cached until the ttl expired.
+	now = now.Add(59 * time.Second)
+
+	value, _ = cached(cache, "foo", fetch)
+	assert.Equal(t, 1, value)
+
+	value, _ = cached(cache, "bar", fetch)
+	assert.Equal(t, 2, value)
+
+	now = now.Add(time.Second)
+
+	value, _ = cached(cache, "foo", fetch)
+	assert.Equal(t, 3, value)
+
+	// Errors are not cached.
+	_, err = cached(cache, "baz", func() (int, error) { return 0, errors.New("error") })
+	assert.Error(t, err)
+
+	value, err = cached(cache, "baz", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, value)
+}
+
+func TestResponseCacheConcurrentLookups(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	release := make(chan struct{})
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			value, err := cached(cache, "foo", func() (string, error) {
+				calls.Add(1)
+				<-release
+
+				return "value", nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "value", value)
+		}()
+	}
+
+	// Give all goroutines the chance to look up the key before the first
+	// request completes.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+type mockCachedOceanAWSClient struct {
+	*mockOceanAWSClustersClient
+	*mockOceanAWSResourceSuggestionsClient
+	*mockOceanAWSClusterNodesClient
+	*mockOceanAWSLaunchSpecsClient
+}
+
+func TestCachingClient(t *testing.T) {
+	costsClient := new(mockOceanClusterCostsClient)
+	costsClient.On("GetClusterCosts", mock.Anything, clusterCostInput("foo")).Return(clusterCostOutput(100), nil).Once()
+	costsClient.On("GetClusterCosts", mock.Anything, clusterCostInput("bar")).Return(clusterCostOutput(200), nil).Once()
+
+	suggestionsClient := new(mockOceanAWSResourceSuggestionsClient)
+	suggestionsClient.On("ListOceanResourceSuggestions", mock.Anything, resourceSuggestionsInput("foo")).
+		Return(resourceSuggestionsOutput(), nil).Once()
+
+	nodesClient := new(mockOceanAWSClusterNodesClient)
+	nodesClient.On("ReadClusterNodes", mock.Anything, &aws.ReadClusterNodeInput{ClusterID: spotinst.String("foo")}).
+		Return(&aws.ReadClusterNodeOutput{}, nil).Once()
+
+	launchSpecsClient := new(mockOceanAWSLaunchSpecsClient)
+	launchSpecsClient.On("ListLaunchSpecs", mock.Anything, &aws.ListLaunchSpecsInput{OceanID: spotinst.String("foo")}).
+		Return(&aws.ListLaunchSpecsOutput{}, nil).Once()
+
+	clustersClient := new(mockOceanAWSClustersClient)
+	clustersClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(&aws.ListClustersOutput{}, nil).Once()
+
+	oceanClient := &mockCachedOceanAWSClient{clustersClient, suggestionsClient, nodesClient, launchSpecsClient}
+	client := NewCachingClient(costsClient, oceanClient, time.Minute)
+	ctx := context.Background()
+
+	for range 2 {
+		output, err := client.GetClusterCosts(ctx, clusterCostInput("foo"))
+		assert.NoError(t, err)
+		assert.Equal(t, clusterCostOutput(100), output)
+
+		output, err = client.GetClusterCosts(ctx, clusterCostInput("bar"))
+		assert.NoError(t, err)
+		assert.Equal(t, clusterCostOutput(200), output)
+
+		_, err = client.ListOceanResourceSuggestions(ctx, resourceSuggestionsInput("foo"))
+		assert.NoError(t, err)
+
+		_, err = client.ListClusters(ctx, &aws.ListClustersInput{})
+		assert.NoError(t, err)
+
+		_, err = client.ReadClusterNodes(ctx, &aws.ReadClusterNodeInput{ClusterID: spotinst.String("foo")})
+		assert.NoError(t, err)
+
+		_, err = client.ListLaunchSpecs(ctx, &aws.ListLaunchSpecsInput{OceanID: spotinst.String("foo")})
+		assert.NoError(t, err)
+	}
+
+	costsClient.AssertExpectations(t)
+	suggestionsClient.AssertExpectations(t)
+	clustersClient.AssertExpectations(t)
+	nodesClient.AssertExpectations(t)
+	launchSpecsClient.AssertExpectations(t)
+}
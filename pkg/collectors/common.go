@@ -1,7 +1,17 @@
 // Package collectors contains Prometheus collectors for Spotinst metrics.
 package collectors
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxConcurrentRequests is the maximum number of concurrent requests a
+// collector sends to the Spotinst API, e.g. when fetching the status of every
+// listed group.
+const maxConcurrentRequests = 8
 
 func collectGaugeValue(
 	ch chan<- prometheus.Metric,
@@ -16,3 +26,167 @@ func collectGaugeValue(
 		labelValues...,
 	)
 }
+
+func collectCounterValue(
+	ch chan<- prometheus.Metric,
+	desc *prometheus.Desc,
+	value float64,
+	labelValues []string,
+) {
+	ch <- prometheus.MustNewConstMetric(
+		desc,
+		prometheus.CounterValue,
+		value,
+		labelValues...,
+	)
+}
+
+// collectOptionalGaugeValue collects value multiplied by factor, unless value
+// is nil.
+func collectOptionalGaugeValue(
+	ch chan<- prometheus.Metric,
+	desc *prometheus.Desc,
+	value *int,
+	factor float64,
+	labelValues []string,
+) {
+	if value == nil {
+		return
+	}
+
+	collectGaugeValue(ch, desc, float64(*value)*factor, labelValues)
+}
+
+// formatOptionalBool formats value as label value, returning an empty string
+// if value is nil.
+func formatOptionalBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*value)
+}
+
+// formatOptionalFloat formats value as label value, returning an empty string
+// if value is nil.
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// formatOptionalInt formats value as label value, returning an empty string
+// if value is nil.
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*value)
+}
+
+// fetchResult holds the response or error of a request to the Spotinst API.
+type fetchResult[T any] struct {
+	value T
+	err   error
+}
+
+// fetchConcurrently calls fetch for every item with at most
+// maxConcurrentRequests calls running at the same time and returns the
+// results in the order of the items.
+func fetchConcurrently[I, T any](items []I, fetch func(I) (T, error)) []fetchResult[T] {
+	results := make([]fetchResult[T], len(items))
+	semaphore := make(chan struct{}, maxConcurrentRequests)
+
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		semaphore <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			value, err := fetch(item)
+			results[i] = fetchResult[T]{value: value, err: err}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// KubernetesMetadataProvider provides the Kubernetes labels, annotations and
+// owners of namespaces and workloads, as well as the resource limits of their
+// containers.
+//
+// It is implemented by *enrichment.Store.
+type KubernetesMetadataProvider interface {
+	NamespaceMetadata(clusterID, namespace string) map[string]string
+	WorkloadMetadata(clusterID, namespace, workload, name string) map[string]string
+	WorkloadOwner(clusterID, namespace, workload, name string) (string, string)
+	ContainerLimits(clusterID, namespace, workload, name, container string) (float64, float64)
+}
+
+// noopMetadataProvider is used if Kubernetes enrichment is disabled.
+type noopMetadataProvider struct{}
+
+func (noopMetadataProvider) NamespaceMetadata(string, string) map[string]string {
+	return nil
+}
+
+func (noopMetadataProvider) WorkloadMetadata(string, string, string, string) map[string]string {
+	return nil
+}
+
+func (noopMetadataProvider) WorkloadOwner(string, string, string, string) (string, string) {
+	return "", ""
+}
+
+func (noopMetadataProvider) ContainerLimits(string, string, string, string, string) (float64, float64) {
+	return 0, 0
+}
+
+// mergeLabels merges the provided label maps into a new map. Values of later
+// maps take precedence.
+func mergeLabels(labelMaps ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+
+	for _, labels := range labelMaps {
+		for key, value := range labels {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// ApplicableConstLabels returns the constant labels which can be attached to
+// the metrics of the collector. Labels which the collector already attaches to
+// one of its metrics itself, e.g. the region of a cluster, are left out, as
+// their values must not be overridden.
+//
+// Whether a label is already used is determined by registering the collector
+// with a temporary registry which attaches the label, which fails if any of
+// the descriptors of the collector uses the label name.
+func ApplicableConstLabels(collector prometheus.Collector, constLabels prometheus.Labels) prometheus.Labels {
+	applicable := make(prometheus.Labels, len(constLabels))
+
+	for name, value := range constLabels {
+		registerer := prometheus.WrapRegistererWith(prometheus.Labels{name: value}, prometheus.NewRegistry())
+		if err := registerer.Register(collector); err != nil {
+			continue
+		}
+
+		applicable[name] = value
+	}
+
+	return applicable
+}
@@ -0,0 +1,107 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestApplicableConstLabels ensures that collectors can be registered with
+// their applicable constant labels, even if the constant labels use the name
+// of a label of the exported metrics.
+func TestApplicableConstLabels(t *testing.T) {
+	ctx := context.Background()
+	logger := zapr.NewLogger(zap.NewNop())
+
+	allCollectors := []prometheus.Collector{
+		NewOceanAWSClusterCostsCollector(ctx, logger, nil, nil, labels.Resolver{}, nil, nil, false),
+		NewOceanAWSResourceSuggestionsCollector(ctx, logger, nil, nil, labels.Resolver{}, nil, false, ResourceSuggestionsOptions{}),
+		NewOceanAWSRightsizingSavingsCollector(ctx, logger, nil, nil, nil, labels.Resolver{}, nil, nil, false, ResourceSuggestionsOptions{}),
+		NewOceanAWSClusterNodesCollector(ctx, logger, nil, nil),
+		NewOceanAWSLaunchSpecsCollector(ctx, logger, nil, nil, nil),
+		NewOceanAWSClusterInfoCollector(ctx, logger, nil, nil),
+		NewOceanAWSInstanceMixCollector(ctx, logger, nil, nil, nil, nil),
+		NewOceanAWSAutoscalerEventsCollector(ctx, logger, nil, nil),
+		NewOceanAWSRollsCollector(ctx, logger, nil, nil),
+		NewOceanAWSHeadroomCollector(ctx, logger, nil, nil, nil, nil),
+		NewOceanGCPClusterCostsCollector(ctx, logger, nil, nil, labels.Resolver{}, nil, nil, false),
+		NewOceanAzureClusterCostsCollector(ctx, logger, nil, nil, labels.Resolver{}, nil, nil, false),
+		NewOceanAzureClusterInfoCollector(ctx, logger, nil, nil),
+		NewOceanAzureVirtualNodeGroupsCollector(ctx, logger, nil, nil),
+		NewElastigroupAWSCollector(ctx, logger, nil, nil),
+		NewElastigroupGCPCollector(ctx, logger, nil, nil),
+		NewElastigroupAzureCollector(ctx, logger, nil, nil),
+		NewStatefulNodeAWSCollector(ctx, logger, nil),
+		NewOceanSparkCollector(ctx, logger, nil, nil, time.Hour),
+		NewOceanCDCollector(ctx, logger, nil, nil),
+	}
+
+	constLabels := prometheus.Labels{"environment": "prod", "region": "eu-west-1", "ocean_id": "o-12345"}
+
+	for _, collector := range allCollectors {
+		applicable := ApplicableConstLabels(collector, constLabels)
+		assert.Equal(t, "prod", applicable["environment"], "constant label dropped for %T", collector)
+
+		registerer := prometheus.WrapRegistererWith(applicable, prometheus.NewRegistry())
+		assert.NoError(t, registerer.Register(collector))
+	}
+
+	assert.Equal(
+		t,
+		prometheus.Labels{"environment": "prod"},
+		ApplicableConstLabels(NewOceanAWSClusterInfoCollector(ctx, logger, nil, nil), constLabels),
+	)
+	assert.Equal(
+		t,
+		prometheus.Labels{"environment": "prod", "ocean_id": "o-12345"},
+		ApplicableConstLabels(NewStatefulNodeAWSCollector(ctx, logger, nil), constLabels),
+	)
+}
+
+func TestFetchConcurrently(t *testing.T) {
+	items := make([]int, 3*maxConcurrentRequests)
+	for i := range items {
+		items[i] = i
+	}
+
+	var running, maxRunning atomic.Int32
+
+	results := fetchConcurrently(items, func(item int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		if item == 1 {
+			return 0, errors.New("error")
+		}
+
+		return item * 2, nil
+	})
+
+	assert.LessOrEqual(t, maxRunning.Load(), int32(maxConcurrentRequests))
+	assert.Len(t, results, len(items))
+	assert.Error(t, results[1].err)
+
+	for i, result := range results {
+		if i != 1 {
+			assert.NoError(t, result.err)
+			assert.Equal(t, i*2, result.value)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// elastigroup is the cloud provider independent representation of an
+// Elastigroup. The provider specific collectors translate the groups returned
+// by the Spotinst SDK into it, so that the groups of all cloud providers can
+// be emitted by the same code.
+type elastigroup struct {
+	id   string
+	name string
+	// info holds the values of the provider specific labels of the group
+	// info metric, in the order of the infoLabelNames passed to
+	// newElastigroupCollector.
+	info           []string
+	tags           map[string]string
+	minCapacity    *int
+	maxCapacity    *int
+	targetCapacity *int
+}
+
+// elastigroupInstance is the cloud provider independent representation of a
+// running instance of an Elastigroup.
+type elastigroupInstance struct {
+	lifecycle    string
+	instanceType string
+}
+
+// elastigroupProvider lists the Elastigroups of a cloud provider and the
+// instances of a group in their cloud provider independent representation.
+type elastigroupProvider interface {
+	listGroups(ctx context.Context) ([]elastigroup, error)
+	listInstances(ctx context.Context, groupID string) ([]elastigroupInstance, error)
+}
+
+// elastigroupCollector collects the configuration and instances of
+// Elastigroups. It implements the Elastigroup collectors of all cloud
+// providers, which only differ in the way groups and instances are listed.
+//
+// Groups are listed on every collection, as the target capacity of
+// Elastigroups changes constantly.
+type elastigroupCollector struct {
+	ctx            context.Context
+	logger         logr.Logger
+	provider       elastigroupProvider
+	groupTags      labels.Mappings
+	info           *prometheus.Desc
+	minCapacity    *prometheus.Desc
+	maxCapacity    *prometheus.Desc
+	targetCapacity *prometheus.Desc
+	instances      *prometheus.Desc
+}
+
+// newElastigroupCollector creates a new elastigroupCollector emitting metrics
+// with the subsystem of the cloud provider, e.g. "elastigroup_aws". The
+// infoLabelNames are the provider specific labels of the group info metric,
+// and the groupTags are used to propagate the tags of the groups onto it.
+func newElastigroupCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	cloud string,
+	provider elastigroupProvider,
+	infoLabelNames []string,
+	groupTags labels.Mappings,
+) elastigroupCollector {
+	subsystem := "elastigroup_" + cloud
+	labelNames := []string{"elastigroup_id", "elastigroup_name"}
+
+	return elastigroupCollector{
+		ctx:       ctx,
+		logger:    logger,
+		provider:  provider,
+		groupTags: groupTags,
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "group_info"),
+			"Information about the configuration of an elastigroup",
+			append(append(labelNames, infoLabelNames...), groupTags.LabelNames()...),
+			nil,
+		),
+		minCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "group_capacity_min"),
+			"The configured minimum capacity of an elastigroup",
+			labelNames,
+			nil,
+		),
+		maxCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "group_capacity_max"),
+			"The configured maximum capacity of an elastigroup",
+			labelNames,
+			nil,
+		),
+		targetCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "group_capacity_target"),
+			"The target capacity of an elastigroup",
+			labelNames,
+			nil,
+		),
+		instances: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "group_instances"),
+			"The number of running instances of an elastigroup per lifecycle and instance type",
+			append(labelNames, "lifecycle", "instance_type"),
+			nil,
+		),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *elastigroupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.minCapacity
+	ch <- c.maxCapacity
+	ch <- c.targetCapacity
+	ch <- c.instances
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *elastigroupCollector) Collect(ch chan<- prometheus.Metric) {
+	groups, err := c.provider.listGroups(c.ctx)
+	if err != nil {
+		c.logger.Error(err, "failed to list elastigroups")
+		return
+	}
+
+	instances := fetchConcurrently(groups, func(group elastigroup) ([]elastigroupInstance, error) {
+		return c.provider.listInstances(c.ctx, group.id)
+	})
+
+	for i, group := range groups {
+		c.collectGroupInfo(ch, group)
+
+		result := instances[i]
+		if result.err != nil {
+			c.logger.Error(result.err, "failed to fetch elastigroup status", "elastigroup_id", group.id)
+			continue
+		}
+
+		c.collectInstances(ch, result.value, group)
+	}
+}
+
+func (c *elastigroupCollector) collectGroupInfo(ch chan<- prometheus.Metric, group elastigroup) {
+	labelValues := []string{group.id, group.name}
+	infoLabelValues := append(append(labelValues, group.info...), c.groupTags.LabelValues(group.tags)...)
+
+	collectGaugeValue(ch, c.info, 1, infoLabelValues)
+	collectOptionalGaugeValue(ch, c.minCapacity, group.minCapacity, 1, labelValues)
+	collectOptionalGaugeValue(ch, c.maxCapacity, group.maxCapacity, 1, labelValues)
+	collectOptionalGaugeValue(ch, c.targetCapacity, group.targetCapacity, 1, labelValues)
+}
+
+func (c *elastigroupCollector) collectInstances(
+	ch chan<- prometheus.Metric,
+	instances []elastigroupInstance,
+	group elastigroup,
+) {
+	counts := make(map[elastigroupInstance]float64)
+
+	for _, instance := range instances {
+		counts[instance]++
+	}
+
+	for instance, count := range counts {
+		collectGaugeValue(ch, c.instances, count, []string{
+			group.id,
+			group.name,
+			instance.lifecycle,
+			instance.instanceType,
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	elastigroupaws "github.com/spotinst/spotinst-sdk-go/service/elastigroup/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// ElastigroupAWSClient is the interface for listing Elastigroups and the
+// status of their instances.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client of the elastigroup
+// service.
+type ElastigroupAWSClient interface {
+	List(context.Context, *elastigroupaws.ListGroupsInput) (*elastigroupaws.ListGroupsOutput, error)
+	Status(context.Context, *elastigroupaws.StatusGroupInput) (*elastigroupaws.StatusGroupOutput, error)
+}
+
+// ElastigroupAWSCollector is a prometheus collector for the configuration and
+// instances of Spotinst Elastigroups on AWS.
+//
+// Like the OceanAWSClusterInfoCollector, it lists the groups on every
+// collection, as the target capacity of Elastigroups changes constantly.
+type ElastigroupAWSCollector struct {
+	elastigroupCollector
+}
+
+// NewElastigroupAWSCollector creates a new ElastigroupAWSCollector for
+// collecting all Elastigroups of the account. The groupTags are used to
+// propagate the tags of the groups onto the group info metric, like the
+// cluster tags of Ocean clusters.
+func NewElastigroupAWSCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client ElastigroupAWSClient,
+	groupTags labels.Mappings,
+) *ElastigroupAWSCollector {
+	return &ElastigroupAWSCollector{
+		elastigroupCollector: newElastigroupCollector(
+			ctx,
+			logger,
+			cloudAWS,
+			&elastigroupAWSProvider{client: client},
+			[]string{"region", "product", "spot_percentage", "on_demand_count", "fallback_to_on_demand"},
+			groupTags,
+		),
+	}
+}
+
+// elastigroupAWSProvider translates the Elastigroups on AWS and their
+// instances into their cloud provider independent representation.
+type elastigroupAWSProvider struct {
+	client ElastigroupAWSClient
+}
+
+func (p *elastigroupAWSProvider) listGroups(ctx context.Context) ([]elastigroup, error) {
+	output, err := p.client.List(ctx, &elastigroupaws.ListGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]elastigroup, 0, len(output.Groups))
+	for _, group := range output.Groups {
+		groups = append(groups, elastigroupAWSGroup(group))
+	}
+
+	return groups, nil
+}
+
+func (p *elastigroupAWSProvider) listInstances(ctx context.Context, groupID string) ([]elastigroupInstance, error) {
+	output, err := p.client.Status(ctx, &elastigroupaws.StatusGroupInput{
+		GroupID: spotinst.String(groupID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]elastigroupInstance, 0, len(output.Instances))
+	for _, instance := range output.Instances {
+		instances = append(instances, elastigroupInstance{
+			lifecycle:    elastigroupInstanceLifecycle(instance),
+			instanceType: spotinst.StringValue(instance.InstanceType),
+		})
+	}
+
+	return instances, nil
+}
+
+// elastigroupAWSGroup translates an Elastigroup on AWS into its cloud
+// provider independent representation.
+func elastigroupAWSGroup(group *elastigroupaws.Group) elastigroup {
+	var product, spotPercentage, onDemandCount, fallbackToOnDemand string
+
+	if compute := group.Compute; compute != nil {
+		product = spotinst.StringValue(compute.Product)
+	}
+
+	if strategy := group.Strategy; strategy != nil {
+		spotPercentage = formatOptionalFloat(strategy.Risk)
+		onDemandCount = formatOptionalInt(strategy.OnDemandCount)
+		fallbackToOnDemand = formatOptionalBool(strategy.FallbackToOnDemand)
+	}
+
+	result := elastigroup{
+		id:   spotinst.StringValue(group.ID),
+		name: spotinst.StringValue(group.Name),
+		info: []string{
+			spotinst.StringValue(group.Region),
+			product,
+			spotPercentage,
+			onDemandCount,
+			fallbackToOnDemand,
+		},
+		tags: elastigroupAWSTags(group),
+	}
+
+	if capacity := group.Capacity; capacity != nil {
+		result.minCapacity = capacity.Minimum
+		result.maxCapacity = capacity.Maximum
+		result.targetCapacity = capacity.Target
+	}
+
+	return result
+}
+
+// elastigroupInstanceLifecycle returns the lifecycle of an Elastigroup
+// instance. The group status does not report the lifecycle, but only spot
+// instances have a spot instance request.
+func elastigroupInstanceLifecycle(instance *elastigroupaws.Instance) string {
+	if spotinst.StringValue(instance.SpotRequestID) != "" {
+		return lifecycleSpot
+	}
+
+	return lifecycleOnDemand
+}
+
+// elastigroupAWSTags returns the tags configured on the launch specification
+// of an Elastigroup as a map.
+func elastigroupAWSTags(group *elastigroupaws.Group) map[string]string {
+	if group.Compute == nil || group.Compute.LaunchSpecification == nil {
+		return nil
+	}
+
+	tags := group.Compute.LaunchSpecification.Tags
+	tagMap := make(map[string]string, len(tags))
+
+	for _, tag := range tags {
+		tagMap[spotinst.StringValue(tag.Key)] = spotinst.StringValue(tag.Value)
+	}
+
+	return tagMap
+}
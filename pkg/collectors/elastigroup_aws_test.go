@@ -0,0 +1,162 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	elastigroupaws "github.com/spotinst/spotinst-sdk-go/service/elastigroup/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockElastigroupAWSClient struct {
+	mock.Mock
+}
+
+func (m *mockElastigroupAWSClient) List(
+	ctx context.Context,
+	input *elastigroupaws.ListGroupsInput,
+) (*elastigroupaws.ListGroupsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*elastigroupaws.ListGroupsOutput), args.Error(1)
+}
+
+func (m *mockElastigroupAWSClient) Status(
+	ctx context.Context,
+	input *elastigroupaws.StatusGroupInput,
+) (*elastigroupaws.StatusGroupOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*elastigroupaws.StatusGroupOutput), args.Error(1)
+}
+
+func TestElastigroupAWSCollector(t *testing.T) {
+	testCases := []struct {
+		name      string
+		client    func() ElastigroupAWSClient
+		groupTags labels.Mappings
+		expected  string
+	}{
+		{
+			name: "failing group list",
+			client: func() ElastigroupAWSClient {
+				mockClient := new(mockElastigroupAWSClient)
+				mockClient.On("List", mock.Anything, &elastigroupaws.ListGroupsInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "failing group status",
+			client: func() ElastigroupAWSClient {
+				output := &elastigroupaws.ListGroupsOutput{Groups: []*elastigroupaws.Group{
+					{ID: spotinst.String("sig-foo"), Name: spotinst.String("foo")},
+				}}
+
+				mockClient := new(mockElastigroupAWSClient)
+				mockClient.On("List", mock.Anything, &elastigroupaws.ListGroupsInput{}).Return(output, nil)
+				mockClient.On("Status", mock.Anything, groupStatusInput("sig-foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_elastigroup_aws_group_info Information about the configuration of an elastigroup
+                # TYPE spotinst_elastigroup_aws_group_info gauge
+                spotinst_elastigroup_aws_group_info{elastigroup_id="sig-foo",elastigroup_name="foo",fallback_to_on_demand="",on_demand_count="",product="",region="",spot_percentage=""} 1
+            `,
+		},
+		{
+			name: "group with configuration and instances",
+			client: func() ElastigroupAWSClient {
+				output := &elastigroupaws.ListGroupsOutput{Groups: []*elastigroupaws.Group{
+					{
+						ID:     spotinst.String("sig-foo"),
+						Name:   spotinst.String("foo"),
+						Region: spotinst.String("eu-west-1"),
+						Capacity: &elastigroupaws.Capacity{
+							Minimum: spotinst.Int(1),
+							Maximum: spotinst.Int(10),
+							Target:  spotinst.Int(3),
+						},
+						Strategy: &elastigroupaws.Strategy{
+							Risk:               spotinst.Float64(100),
+							OnDemandCount:      spotinst.Int(1),
+							FallbackToOnDemand: spotinst.Bool(true),
+						},
+						Compute: &elastigroupaws.Compute{
+							Product: spotinst.String("Linux/UNIX"),
+							LaunchSpecification: &elastigroupaws.LaunchSpecification{
+								Tags: []*elastigroupaws.Tag{
+									{Key: spotinst.String("team"), Value: spotinst.String("platform")},
+								},
+							},
+						},
+					},
+				}}
+				status := &elastigroupaws.StatusGroupOutput{Instances: []*elastigroupaws.Instance{
+					{ID: spotinst.String("i-1"), InstanceType: spotinst.String("m5.large"), SpotRequestID: spotinst.String("sir-1")},
+					{ID: spotinst.String("i-2"), InstanceType: spotinst.String("m5.large"), SpotRequestID: spotinst.String("sir-2")},
+					{ID: spotinst.String("i-3"), InstanceType: spotinst.String("m5.large")},
+				}}
+
+				mockClient := new(mockElastigroupAWSClient)
+				mockClient.On("List", mock.Anything, &elastigroupaws.ListGroupsInput{}).Return(output, nil)
+				mockClient.On("Status", mock.Anything, groupStatusInput("sig-foo")).Return(status, nil)
+				return mockClient
+			},
+			groupTags: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_elastigroup_aws_group_capacity_max The configured maximum capacity of an elastigroup
+                # TYPE spotinst_elastigroup_aws_group_capacity_max gauge
+                spotinst_elastigroup_aws_group_capacity_max{elastigroup_id="sig-foo",elastigroup_name="foo"} 10
+                # HELP spotinst_elastigroup_aws_group_capacity_min The configured minimum capacity of an elastigroup
+                # TYPE spotinst_elastigroup_aws_group_capacity_min gauge
+                spotinst_elastigroup_aws_group_capacity_min{elastigroup_id="sig-foo",elastigroup_name="foo"} 1
+                # HELP spotinst_elastigroup_aws_group_capacity_target The target capacity of an elastigroup
+                # TYPE spotinst_elastigroup_aws_group_capacity_target gauge
+                spotinst_elastigroup_aws_group_capacity_target{elastigroup_id="sig-foo",elastigroup_name="foo"} 3
+                # HELP spotinst_elastigroup_aws_group_info Information about the configuration of an elastigroup
+                # TYPE spotinst_elastigroup_aws_group_info gauge
+                spotinst_elastigroup_aws_group_info{elastigroup_id="sig-foo",elastigroup_name="foo",fallback_to_on_demand="true",on_demand_count="1",product="Linux/UNIX",region="eu-west-1",spot_percentage="100",team="platform"} 1
+                # HELP spotinst_elastigroup_aws_group_instances The number of running instances of an elastigroup per lifecycle and instance type
+                # TYPE spotinst_elastigroup_aws_group_instances gauge
+                spotinst_elastigroup_aws_group_instances{elastigroup_id="sig-foo",elastigroup_name="foo",instance_type="m5.large",lifecycle="on-demand"} 1
+                spotinst_elastigroup_aws_group_instances{elastigroup_id="sig-foo",elastigroup_name="foo",instance_type="m5.large",lifecycle="spot"} 2
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewElastigroupAWSCollector(ctx, logger, testCase.client(), testCase.groupTags)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func groupStatusInput(groupID string) *elastigroupaws.StatusGroupInput {
+	return &elastigroupaws.StatusGroupInput{GroupID: spotinst.String(groupID)}
+}
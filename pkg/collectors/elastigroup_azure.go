@@ -0,0 +1,136 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	elastigroupazure "github.com/spotinst/spotinst-sdk-go/service/elastigroup/providers/azure/v3"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// ElastigroupAzureClient is the interface for listing Elastigroups on Azure
+// and the status of their virtual machines.
+//
+// It is implemented by the Spotinst *v3.ServiceOp client of the elastigroup
+// service.
+type ElastigroupAzureClient interface {
+	List(context.Context, *elastigroupazure.ListGroupsInput) (*elastigroupazure.ListGroupsOutput, error)
+	Status(context.Context, *elastigroupazure.StatusGroupInput) (*elastigroupazure.StatusGroupOutput, error)
+}
+
+// ElastigroupAzureCollector is a prometheus collector for the configuration
+// and virtual machines of Spotinst Elastigroups on Azure. Like the
+// ElastigroupAWSCollector, it lists the groups on every collection.
+type ElastigroupAzureCollector struct {
+	elastigroupCollector
+}
+
+// NewElastigroupAzureCollector creates a new ElastigroupAzureCollector for
+// collecting all Elastigroups on Azure of the account. The groupTags are used
+// to propagate the tags of the groups onto the group info metric.
+func NewElastigroupAzureCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client ElastigroupAzureClient,
+	groupTags labels.Mappings,
+) *ElastigroupAzureCollector {
+	return &ElastigroupAzureCollector{
+		elastigroupCollector: newElastigroupCollector(
+			ctx,
+			logger,
+			cloudAzure,
+			&elastigroupAzureProvider{client: client},
+			[]string{"region", "resource_group", "spot_percentage", "on_demand_count", "fallback_to_on_demand"},
+			groupTags,
+		),
+	}
+}
+
+// elastigroupAzureProvider translates the Elastigroups on Azure and their
+// virtual machines into their cloud provider independent representation.
+type elastigroupAzureProvider struct {
+	client ElastigroupAzureClient
+}
+
+func (p *elastigroupAzureProvider) listGroups(ctx context.Context) ([]elastigroup, error) {
+	output, err := p.client.List(ctx, &elastigroupazure.ListGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]elastigroup, 0, len(output.Groups))
+	for _, group := range output.Groups {
+		groups = append(groups, elastigroupAzureGroup(group))
+	}
+
+	return groups, nil
+}
+
+func (p *elastigroupAzureProvider) listInstances(ctx context.Context, groupID string) ([]elastigroupInstance, error) {
+	output, err := p.client.Status(ctx, &elastigroupazure.StatusGroupInput{
+		GroupID: spotinst.String(groupID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]elastigroupInstance, 0, len(output.VMs))
+	for _, vm := range output.VMs {
+		instances = append(instances, elastigroupInstance{
+			lifecycle:    normalizeLifecycle(spotinst.StringValue(vm.LifeCycle)),
+			instanceType: spotinst.StringValue(vm.VMSize),
+		})
+	}
+
+	return instances, nil
+}
+
+// elastigroupAzureGroup translates an Elastigroup on Azure into its cloud
+// provider independent representation.
+func elastigroupAzureGroup(group *elastigroupazure.Group) elastigroup {
+	var spotPercentage, onDemandCount, fallbackToOnDemand string
+
+	if strategy := group.Strategy; strategy != nil {
+		spotPercentage = formatOptionalInt(strategy.SpotPercentage)
+		onDemandCount = formatOptionalInt(strategy.OnDemandCount)
+		fallbackToOnDemand = formatOptionalBool(strategy.FallbackToOnDemand)
+	}
+
+	result := elastigroup{
+		id:   spotinst.StringValue(group.ID),
+		name: spotinst.StringValue(group.Name),
+		info: []string{
+			spotinst.StringValue(group.Region),
+			spotinst.StringValue(group.ResourceGroupName),
+			spotPercentage,
+			onDemandCount,
+			fallbackToOnDemand,
+		},
+		tags: elastigroupAzureTags(group),
+	}
+
+	if capacity := group.Capacity; capacity != nil {
+		result.minCapacity = capacity.Minimum
+		result.maxCapacity = capacity.Maximum
+		result.targetCapacity = capacity.Target
+	}
+
+	return result
+}
+
+// elastigroupAzureTags returns the tags configured on an Elastigroup as a
+// map.
+func elastigroupAzureTags(group *elastigroupazure.Group) map[string]string {
+	if group.Compute == nil {
+		return nil
+	}
+
+	tagMap := make(map[string]string, len(group.Compute.Tags))
+
+	for _, tag := range group.Compute.Tags {
+		tagMap[spotinst.StringValue(tag.TagKey)] = spotinst.StringValue(tag.TagValue)
+	}
+
+	return tagMap
+}
@@ -0,0 +1,159 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	elastigroupazure "github.com/spotinst/spotinst-sdk-go/service/elastigroup/providers/azure/v3"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockElastigroupAzureClient struct {
+	mock.Mock
+}
+
+func (m *mockElastigroupAzureClient) List(
+	ctx context.Context,
+	input *elastigroupazure.ListGroupsInput,
+) (*elastigroupazure.ListGroupsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*elastigroupazure.ListGroupsOutput), args.Error(1)
+}
+
+func (m *mockElastigroupAzureClient) Status(
+	ctx context.Context,
+	input *elastigroupazure.StatusGroupInput,
+) (*elastigroupazure.StatusGroupOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*elastigroupazure.StatusGroupOutput), args.Error(1)
+}
+
+func TestElastigroupAzureCollector(t *testing.T) {
+	testCases := []struct {
+		name      string
+		client    func() ElastigroupAzureClient
+		groupTags labels.Mappings
+		expected  string
+	}{
+		{
+			name: "failing group list",
+			client: func() ElastigroupAzureClient {
+				mockClient := new(mockElastigroupAzureClient)
+				mockClient.On("List", mock.Anything, &elastigroupazure.ListGroupsInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "failing group status",
+			client: func() ElastigroupAzureClient {
+				output := &elastigroupazure.ListGroupsOutput{Groups: []*elastigroupazure.Group{
+					{ID: spotinst.String("sig-foo"), Name: spotinst.String("foo")},
+				}}
+
+				mockClient := new(mockElastigroupAzureClient)
+				mockClient.On("List", mock.Anything, &elastigroupazure.ListGroupsInput{}).Return(output, nil)
+				mockClient.On("Status", mock.Anything, azureGroupStatusInput("sig-foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_elastigroup_azure_group_info Information about the configuration of an elastigroup
+                # TYPE spotinst_elastigroup_azure_group_info gauge
+                spotinst_elastigroup_azure_group_info{elastigroup_id="sig-foo",elastigroup_name="foo",fallback_to_on_demand="",on_demand_count="",region="",resource_group="",spot_percentage=""} 1
+            `,
+		},
+		{
+			name: "group with configuration and instances",
+			client: func() ElastigroupAzureClient {
+				output := &elastigroupazure.ListGroupsOutput{Groups: []*elastigroupazure.Group{
+					{
+						ID:                spotinst.String("sig-foo"),
+						Name:              spotinst.String("foo"),
+						Region:            spotinst.String("westeurope"),
+						ResourceGroupName: spotinst.String("foo-rg"),
+						Capacity: &elastigroupazure.Capacity{
+							Minimum: spotinst.Int(1),
+							Maximum: spotinst.Int(10),
+							Target:  spotinst.Int(3),
+						},
+						Strategy: &elastigroupazure.Strategy{
+							SpotPercentage:     spotinst.Int(100),
+							FallbackToOnDemand: spotinst.Bool(true),
+						},
+						Compute: &elastigroupazure.Compute{
+							Tags: []*elastigroupazure.Tag{
+								{TagKey: spotinst.String("team"), TagValue: spotinst.String("platform")},
+							},
+						},
+					},
+				}}
+				status := &elastigroupazure.StatusGroupOutput{VMs: []*elastigroupazure.VM{
+					{VMName: spotinst.String("foo-1"), VMSize: spotinst.String("Standard_D4s_v3"), LifeCycle: spotinst.String("SPOT")},
+					{VMName: spotinst.String("foo-2"), VMSize: spotinst.String("Standard_D4s_v3"), LifeCycle: spotinst.String("SPOT")},
+					{VMName: spotinst.String("foo-3"), VMSize: spotinst.String("Standard_D2s_v3"), LifeCycle: spotinst.String("OD")},
+				}}
+
+				mockClient := new(mockElastigroupAzureClient)
+				mockClient.On("List", mock.Anything, &elastigroupazure.ListGroupsInput{}).Return(output, nil)
+				mockClient.On("Status", mock.Anything, azureGroupStatusInput("sig-foo")).Return(status, nil)
+				return mockClient
+			},
+			groupTags: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_elastigroup_azure_group_capacity_max The configured maximum capacity of an elastigroup
+                # TYPE spotinst_elastigroup_azure_group_capacity_max gauge
+                spotinst_elastigroup_azure_group_capacity_max{elastigroup_id="sig-foo",elastigroup_name="foo"} 10
+                # HELP spotinst_elastigroup_azure_group_capacity_min The configured minimum capacity of an elastigroup
+                # TYPE spotinst_elastigroup_azure_group_capacity_min gauge
+                spotinst_elastigroup_azure_group_capacity_min{elastigroup_id="sig-foo",elastigroup_name="foo"} 1
+                # HELP spotinst_elastigroup_azure_group_capacity_target The target capacity of an elastigroup
+                # TYPE spotinst_elastigroup_azure_group_capacity_target gauge
+                spotinst_elastigroup_azure_group_capacity_target{elastigroup_id="sig-foo",elastigroup_name="foo"} 3
+                # HELP spotinst_elastigroup_azure_group_info Information about the configuration of an elastigroup
+                # TYPE spotinst_elastigroup_azure_group_info gauge
+                spotinst_elastigroup_azure_group_info{elastigroup_id="sig-foo",elastigroup_name="foo",fallback_to_on_demand="true",on_demand_count="",region="westeurope",resource_group="foo-rg",spot_percentage="100",team="platform"} 1
+                # HELP spotinst_elastigroup_azure_group_instances The number of running instances of an elastigroup per lifecycle and instance type
+                # TYPE spotinst_elastigroup_azure_group_instances gauge
+                spotinst_elastigroup_azure_group_instances{elastigroup_id="sig-foo",elastigroup_name="foo",instance_type="Standard_D2s_v3",lifecycle="on-demand"} 1
+                spotinst_elastigroup_azure_group_instances{elastigroup_id="sig-foo",elastigroup_name="foo",instance_type="Standard_D4s_v3",lifecycle="spot"} 2
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewElastigroupAzureCollector(ctx, logger, testCase.client(), testCase.groupTags)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func azureGroupStatusInput(groupID string) *elastigroupazure.StatusGroupInput {
+	return &elastigroupazure.StatusGroupInput{GroupID: spotinst.String(groupID)}
+}
@@ -0,0 +1,144 @@
+package collectors
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	elastigroupgcp "github.com/spotinst/spotinst-sdk-go/service/elastigroup/providers/gcp"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// ElastigroupGCPClient is the interface for listing Elastigroups on GCP and
+// the status of their instances.
+//
+// It is implemented by the Spotinst *gcp.ServiceOp client of the elastigroup
+// service.
+type ElastigroupGCPClient interface {
+	List(context.Context, *elastigroupgcp.ListGroupsInput) (*elastigroupgcp.ListGroupsOutput, error)
+	Status(context.Context, *elastigroupgcp.StatusGroupInput) (*elastigroupgcp.StatusGroupOutput, error)
+}
+
+// ElastigroupGCPCollector is a prometheus collector for the configuration and
+// instances of Spotinst Elastigroups on GCP. Like the ElastigroupAWSCollector,
+// it lists the groups on every collection.
+type ElastigroupGCPCollector struct {
+	elastigroupCollector
+}
+
+// NewElastigroupGCPCollector creates a new ElastigroupGCPCollector for
+// collecting all Elastigroups on GCP of the account. The groupTags are used
+// to propagate the labels of the groups onto the group info metric.
+func NewElastigroupGCPCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client ElastigroupGCPClient,
+	groupTags labels.Mappings,
+) *ElastigroupGCPCollector {
+	return &ElastigroupGCPCollector{
+		elastigroupCollector: newElastigroupCollector(
+			ctx,
+			logger,
+			cloudGCP,
+			&elastigroupGCPProvider{client: client},
+			[]string{"availability_zones", "preemptible_percentage", "on_demand_count", "fallback_to_on_demand"},
+			groupTags,
+		),
+	}
+}
+
+// elastigroupGCPProvider translates the Elastigroups on GCP and their
+// instances into their cloud provider independent representation.
+type elastigroupGCPProvider struct {
+	client ElastigroupGCPClient
+}
+
+func (p *elastigroupGCPProvider) listGroups(ctx context.Context) ([]elastigroup, error) {
+	output, err := p.client.List(ctx, &elastigroupgcp.ListGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]elastigroup, 0, len(output.Groups))
+	for _, group := range output.Groups {
+		groups = append(groups, elastigroupGCPGroup(group))
+	}
+
+	return groups, nil
+}
+
+func (p *elastigroupGCPProvider) listInstances(ctx context.Context, groupID string) ([]elastigroupInstance, error) {
+	output, err := p.client.Status(ctx, &elastigroupgcp.StatusGroupInput{
+		GroupID: spotinst.String(groupID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]elastigroupInstance, 0, len(output.Instances))
+	for _, instance := range output.Instances {
+		instances = append(instances, elastigroupInstance{
+			lifecycle:    normalizeLifecycle(spotinst.StringValue(instance.LifeCycle)),
+			instanceType: spotinst.StringValue(instance.MachineType),
+		})
+	}
+
+	return instances, nil
+}
+
+// elastigroupGCPGroup translates an Elastigroup on GCP into its cloud
+// provider independent representation.
+func elastigroupGCPGroup(group *elastigroupgcp.Group) elastigroup {
+	var zones []string
+	var preemptiblePercentage, onDemandCount, fallbackToOnDemand string
+
+	if compute := group.Compute; compute != nil {
+		zones = slices.Clone(compute.AvailabilityZones)
+		slices.Sort(zones)
+	}
+
+	if strategy := group.Strategy; strategy != nil {
+		preemptiblePercentage = formatOptionalInt(strategy.PreemptiblePercentage)
+		onDemandCount = formatOptionalInt(strategy.OnDemandCount)
+		fallbackToOnDemand = formatOptionalBool(strategy.FallbackToOnDemand)
+	}
+
+	result := elastigroup{
+		id:   spotinst.StringValue(group.ID),
+		name: spotinst.StringValue(group.Name),
+		info: []string{
+			strings.Join(zones, ","),
+			preemptiblePercentage,
+			onDemandCount,
+			fallbackToOnDemand,
+		},
+		tags: elastigroupGCPTags(group),
+	}
+
+	if capacity := group.Capacity; capacity != nil {
+		result.minCapacity = capacity.Minimum
+		result.maxCapacity = capacity.Maximum
+		result.targetCapacity = capacity.Target
+	}
+
+	return result
+}
+
+// elastigroupGCPTags returns the labels configured on the launch
+// specification of an Elastigroup as a map.
+func elastigroupGCPTags(group *elastigroupgcp.Group) map[string]string {
+	if group.Compute == nil || group.Compute.LaunchSpecification == nil {
+		return nil
+	}
+
+	groupLabels := group.Compute.LaunchSpecification.Labels
+	tagMap := make(map[string]string, len(groupLabels))
+
+	for _, label := range groupLabels {
+		tagMap[spotinst.StringValue(label.Key)] = spotinst.StringValue(label.Value)
+	}
+
+	return tagMap
+}
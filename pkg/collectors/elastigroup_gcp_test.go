@@ -0,0 +1,161 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	elastigroupgcp "github.com/spotinst/spotinst-sdk-go/service/elastigroup/providers/gcp"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockElastigroupGCPClient struct {
+	mock.Mock
+}
+
+func (m *mockElastigroupGCPClient) List(
+	ctx context.Context,
+	input *elastigroupgcp.ListGroupsInput,
+) (*elastigroupgcp.ListGroupsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*elastigroupgcp.ListGroupsOutput), args.Error(1)
+}
+
+func (m *mockElastigroupGCPClient) Status(
+	ctx context.Context,
+	input *elastigroupgcp.StatusGroupInput,
+) (*elastigroupgcp.StatusGroupOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*elastigroupgcp.StatusGroupOutput), args.Error(1)
+}
+
+func TestElastigroupGCPCollector(t *testing.T) {
+	testCases := []struct {
+		name      string
+		client    func() ElastigroupGCPClient
+		groupTags labels.Mappings
+		expected  string
+	}{
+		{
+			name: "failing group list",
+			client: func() ElastigroupGCPClient {
+				mockClient := new(mockElastigroupGCPClient)
+				mockClient.On("List", mock.Anything, &elastigroupgcp.ListGroupsInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "failing group status",
+			client: func() ElastigroupGCPClient {
+				output := &elastigroupgcp.ListGroupsOutput{Groups: []*elastigroupgcp.Group{
+					{ID: spotinst.String("sig-foo"), Name: spotinst.String("foo")},
+				}}
+
+				mockClient := new(mockElastigroupGCPClient)
+				mockClient.On("List", mock.Anything, &elastigroupgcp.ListGroupsInput{}).Return(output, nil)
+				mockClient.On("Status", mock.Anything, gcpGroupStatusInput("sig-foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_elastigroup_gcp_group_info Information about the configuration of an elastigroup
+                # TYPE spotinst_elastigroup_gcp_group_info gauge
+                spotinst_elastigroup_gcp_group_info{availability_zones="",elastigroup_id="sig-foo",elastigroup_name="foo",fallback_to_on_demand="",on_demand_count="",preemptible_percentage=""} 1
+            `,
+		},
+		{
+			name: "group with configuration and instances",
+			client: func() ElastigroupGCPClient {
+				output := &elastigroupgcp.ListGroupsOutput{Groups: []*elastigroupgcp.Group{
+					{
+						ID:   spotinst.String("sig-foo"),
+						Name: spotinst.String("foo"),
+						Capacity: &elastigroupgcp.Capacity{
+							Minimum: spotinst.Int(1),
+							Maximum: spotinst.Int(10),
+							Target:  spotinst.Int(3),
+						},
+						Strategy: &elastigroupgcp.Strategy{
+							PreemptiblePercentage: spotinst.Int(80),
+							OnDemandCount:         spotinst.Int(1),
+							FallbackToOnDemand:    spotinst.Bool(true),
+						},
+						Compute: &elastigroupgcp.Compute{
+							AvailabilityZones: []string{"europe-west1-c", "europe-west1-b"},
+							LaunchSpecification: &elastigroupgcp.LaunchSpecification{
+								Labels: []*elastigroupgcp.Label{
+									{Key: spotinst.String("team"), Value: spotinst.String("platform")},
+								},
+							},
+						},
+					},
+				}}
+				status := &elastigroupgcp.StatusGroupOutput{Instances: []*elastigroupgcp.InstanceStatus{
+					{InstanceName: spotinst.String("foo-1"), MachineType: spotinst.String("n2-standard-4"), LifeCycle: spotinst.String("PREEMPTIBLE")},
+					{InstanceName: spotinst.String("foo-2"), MachineType: spotinst.String("n2-standard-4"), LifeCycle: spotinst.String("PREEMPTIBLE")},
+					{InstanceName: spotinst.String("foo-3"), MachineType: spotinst.String("n2-standard-4"), LifeCycle: spotinst.String("ON_DEMAND")},
+				}}
+
+				mockClient := new(mockElastigroupGCPClient)
+				mockClient.On("List", mock.Anything, &elastigroupgcp.ListGroupsInput{}).Return(output, nil)
+				mockClient.On("Status", mock.Anything, gcpGroupStatusInput("sig-foo")).Return(status, nil)
+				return mockClient
+			},
+			groupTags: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_elastigroup_gcp_group_capacity_max The configured maximum capacity of an elastigroup
+                # TYPE spotinst_elastigroup_gcp_group_capacity_max gauge
+                spotinst_elastigroup_gcp_group_capacity_max{elastigroup_id="sig-foo",elastigroup_name="foo"} 10
+                # HELP spotinst_elastigroup_gcp_group_capacity_min The configured minimum capacity of an elastigroup
+                # TYPE spotinst_elastigroup_gcp_group_capacity_min gauge
+                spotinst_elastigroup_gcp_group_capacity_min{elastigroup_id="sig-foo",elastigroup_name="foo"} 1
+                # HELP spotinst_elastigroup_gcp_group_capacity_target The target capacity of an elastigroup
+                # TYPE spotinst_elastigroup_gcp_group_capacity_target gauge
+                spotinst_elastigroup_gcp_group_capacity_target{elastigroup_id="sig-foo",elastigroup_name="foo"} 3
+                # HELP spotinst_elastigroup_gcp_group_info Information about the configuration of an elastigroup
+                # TYPE spotinst_elastigroup_gcp_group_info gauge
+                spotinst_elastigroup_gcp_group_info{availability_zones="europe-west1-b,europe-west1-c",elastigroup_id="sig-foo",elastigroup_name="foo",fallback_to_on_demand="true",on_demand_count="1",preemptible_percentage="80",team="platform"} 1
+                # HELP spotinst_elastigroup_gcp_group_instances The number of running instances of an elastigroup per lifecycle and instance type
+                # TYPE spotinst_elastigroup_gcp_group_instances gauge
+                spotinst_elastigroup_gcp_group_instances{elastigroup_id="sig-foo",elastigroup_name="foo",instance_type="n2-standard-4",lifecycle="on-demand"} 1
+                spotinst_elastigroup_gcp_group_instances{elastigroup_id="sig-foo",elastigroup_name="foo",instance_type="n2-standard-4",lifecycle="preemptible"} 2
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewElastigroupGCPCollector(ctx, logger, testCase.client(), testCase.groupTags)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func gcpGroupStatusInput(groupID string) *elastigroupgcp.StatusGroupInput {
+	return &elastigroupgcp.StatusGroupInput{GroupID: spotinst.String(groupID)}
+}
@@ -0,0 +1,263 @@
+package collectors
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanAWSLogEventsClient is the interface for fetching the log events of an
+// Ocean cluster.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client.
+type OceanAWSLogEventsClient interface {
+	GetLogEvents(context.Context, *aws.GetLogEventsInput) (*aws.GetLogEventsOutput, error)
+}
+
+var (
+	scaleUpRegex      = regexp.MustCompile(`(?i)\bscal(e|ing)[ -]?up\b`)
+	scaleDownRegex    = regexp.MustCompile(`(?i)\bscal(e|ing)[ -]?down\b`)
+	interruptionRegex = regexp.MustCompile(`(?i)\binterrupt(ed|ion)?\b`)
+
+	// scaleEventReasons maps log messages to the value of the `reason`
+	// label. The first matching reason wins, messages not matching any
+	// reason are counted as "other". The set of reasons is fixed to keep
+	// the cardinality of the metrics bounded.
+	scaleEventReasons = []struct {
+		reason string
+		regex  *regexp.Regexp
+	}{
+		{"interruption", interruptionRegex},
+		{"pending_pods", regexp.MustCompile(`(?i)\b(pending|unschedulable) pods?\b`)},
+		{"headroom", regexp.MustCompile(`(?i)\bheadroom\b`)},
+		{"underutilized", regexp.MustCompile(`(?i)\bunder[ -]?utili[sz](ed|ation)\b`)},
+		{"capacity", regexp.MustCompile(`(?i)\b(min|max|minimum|maximum|target) (capacity|nodes|instances)\b`)},
+	}
+)
+
+const (
+	// logEventsGracePeriod is the time by which consecutive polls of the
+	// log events overlap, so that events which are indexed late are still
+	// counted. Events returned by multiple polls are only counted once.
+	logEventsGracePeriod = 5 * time.Minute
+	// logEventsLimit is the maximum number of log events fetched per poll.
+	logEventsLimit = 1000
+)
+
+// scaleEventReason returns the value of the `reason` label for a log message.
+func scaleEventReason(message string) string {
+	for _, reason := range scaleEventReasons {
+		if reason.regex.MatchString(message) {
+			return reason.reason
+		}
+	}
+
+	return "other"
+}
+
+// scaleEventKey identifies a scale event counter of a cluster.
+type scaleEventKey struct {
+	direction string
+	reason    string
+}
+
+// autoscalerEventsState holds the counters of a cluster and the state
+// required to deduplicate log events across polls.
+type autoscalerEventsState struct {
+	// start is the time the state was created. Earlier events are not
+	// counted.
+	start time.Time
+	// from is the time of the last successful poll. The next poll starts
+	// at from minus the grace period.
+	from time.Time
+	// seen holds the log events which may be returned again by the next
+	// poll, as consecutive polls overlap by the grace period.
+	seen           map[string]time.Time
+	scaleEvents    map[scaleEventKey]float64
+	interruptions  float64
+	lastScaleEvent time.Time
+}
+
+// pollStart returns the start of the next poll.
+func (s *autoscalerEventsState) pollStart() time.Time {
+	return s.from.Add(-logEventsGracePeriod).Truncate(time.Second)
+}
+
+// update counts the log events of a poll made at now which have not been
+// seen before.
+func (s *autoscalerEventsState) update(events []*aws.LogEvent, now time.Time) {
+	for _, event := range events {
+		createdAt := spotinst.TimeValue(event.CreatedAt)
+		message := spotinst.StringValue(event.Message)
+
+		id := createdAt.Format(time.RFC3339Nano) + " " + message
+		if _, ok := s.seen[id]; ok || createdAt.Before(s.start.Truncate(time.Second)) {
+			continue
+		}
+
+		s.seen[id] = createdAt
+
+		if interruptionRegex.MatchString(message) {
+			s.interruptions++
+		}
+
+		var direction string
+
+		switch {
+		case scaleUpRegex.MatchString(message):
+			direction = "up"
+		case scaleDownRegex.MatchString(message):
+			direction = "down"
+		default:
+			continue
+		}
+
+		s.scaleEvents[scaleEventKey{direction: direction, reason: scaleEventReason(message)}]++
+
+		if createdAt.After(s.lastScaleEvent) {
+			s.lastScaleEvent = createdAt
+		}
+	}
+
+	s.from = now
+
+	// Events older than the start of the next poll can not be returned
+	// again.
+	for id, createdAt := range s.seen {
+		if createdAt.Before(s.pollStart()) {
+			delete(s.seen, id)
+		}
+	}
+}
+
+// OceanAWSAutoscalerEventsCollector is a prometheus collector for the
+// autoscaler activity of Spotinst Ocean clusters on AWS, derived from the
+// cluster log events.
+//
+// The counters are kept in memory and only include events which happened
+// after the collector was created.
+type OceanAWSAutoscalerEventsCollector struct {
+	ctx            context.Context
+	logger         logr.Logger
+	client         OceanAWSLogEventsClient
+	clusters       []*aws.Cluster
+	now            func() time.Time
+	mu             sync.Mutex
+	states         map[string]*autoscalerEventsState
+	scaleEvents    *prometheus.Desc
+	interruptions  *prometheus.Desc
+	lastScaleEvent *prometheus.Desc
+}
+
+// NewOceanAWSAutoscalerEventsCollector creates a new
+// OceanAWSAutoscalerEventsCollector for collecting the autoscaler events of
+// the provided list of Ocean clusters.
+func NewOceanAWSAutoscalerEventsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAWSLogEventsClient,
+	clusters []*aws.Cluster,
+) *OceanAWSAutoscalerEventsCollector {
+	collector := &OceanAWSAutoscalerEventsCollector{
+		ctx:      ctx,
+		logger:   logger,
+		client:   client,
+		clusters: clusters,
+		now:      time.Now,
+		states:   make(map[string]*autoscalerEventsState, len(clusters)),
+		scaleEvents: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "autoscaler_scale_events_total"),
+			"The number of scale up and scale down events of an ocean cluster",
+			[]string{"ocean_id", "ocean_name", "direction", "reason"},
+			nil,
+		),
+		interruptions: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "spot_interruptions_total"),
+			"The number of spot interruptions of an ocean cluster",
+			[]string{"ocean_id", "ocean_name"},
+			nil,
+		),
+		lastScaleEvent: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "autoscaler_last_scale_event_timestamp_seconds"),
+			"The time of the last scale event of an ocean cluster",
+			[]string{"ocean_id", "ocean_name"},
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSAutoscalerEventsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.scaleEvents
+	ch <- c.interruptions
+	ch <- c.lastScaleEvent
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSAutoscalerEventsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UTC()
+
+	for _, cluster := range c.clusters {
+		clusterID := spotinst.StringValue(cluster.ID)
+
+		state, ok := c.states[clusterID]
+		if !ok {
+			state = &autoscalerEventsState{
+				start:       now,
+				from:        now,
+				seen:        make(map[string]time.Time),
+				scaleEvents: make(map[scaleEventKey]float64),
+			}
+			c.states[clusterID] = state
+		}
+
+		output, err := c.client.GetLogEvents(c.ctx, &aws.GetLogEventsInput{
+			ClusterID: cluster.ID,
+			FromDate:  spotinst.String(state.pollStart().Format(time.RFC3339)),
+			ToDate:    spotinst.String(now.Format(time.RFC3339)),
+			Limit:     spotinst.Int(logEventsLimit),
+		})
+		if err != nil {
+			// The counters collected so far are still exported in order to
+			// avoid gaps.
+			c.logger.Error(err, "failed to fetch log events", "ocean_id", clusterID)
+		} else {
+			if len(output.Events) >= logEventsLimit {
+				c.logger.Info("log events limit reached, events may be missing", "ocean_id", clusterID, "limit", logEventsLimit)
+			}
+
+			state.update(output.Events, now)
+		}
+
+		c.collectState(ch, state, cluster)
+	}
+}
+
+func (c *OceanAWSAutoscalerEventsCollector) collectState(
+	ch chan<- prometheus.Metric,
+	state *autoscalerEventsState,
+	cluster *aws.Cluster,
+) {
+	labelValues := []string{spotinst.StringValue(cluster.ID), spotinst.StringValue(cluster.Name)}
+
+	for key, count := range state.scaleEvents {
+		collectCounterValue(ch, c.scaleEvents, count, append(labelValues, key.direction, key.reason))
+	}
+
+	collectCounterValue(ch, c.interruptions, state.interruptions, labelValues)
+
+	if !state.lastScaleEvent.IsZero() {
+		collectGaugeValue(ch, c.lastScaleEvent, float64(state.lastScaleEvent.Unix()), labelValues)
+	}
+}
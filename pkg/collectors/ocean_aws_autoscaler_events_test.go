@@ -0,0 +1,142 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanAWSLogEventsClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanAWSLogEventsClient) GetLogEvents(
+	ctx context.Context,
+	input *aws.GetLogEventsInput,
+) (*aws.GetLogEventsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*aws.GetLogEventsOutput), args.Error(1)
+}
+
+func TestOceanAWSAutoscalerEventsCollector(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockClient := new(mockOceanAWSLogEventsClient)
+	mockClient.On("GetLogEvents", mock.Anything, logEventsInput("foo", "2025-12-31T23:55:00Z", "2026-01-01T00:00:00Z")).
+		Return(logEventsOutput(
+			// Happened before the collector was created.
+			logEvent(start.Add(-time.Minute), "Scale up: launching 1 instance due to pending pods"),
+		), nil)
+	mockClient.On("GetLogEvents", mock.Anything, logEventsInput("foo", "2025-12-31T23:55:00Z", "2026-01-01T00:01:00Z")).
+		Return(logEventsOutput(
+			logEvent(start.Add(-time.Minute), "Scale up: launching 1 instance due to pending pods"),
+			logEvent(start.Add(10*time.Second), "Scale up: launching 2 instances due to pending pods"),
+			logEvent(start.Add(20*time.Second), "Scale down: instance i-123 is underutilized"),
+			logEvent(start.Add(30*time.Second), "Spot instance i-456 was interrupted, replacing instance"),
+			logEvent(start.Add(30*time.Second), "Cluster roll started"),
+		), nil)
+	mockClient.On("GetLogEvents", mock.Anything, logEventsInput("foo", "2025-12-31T23:56:00Z", "2026-01-01T00:02:00Z")).
+		Return(logEventsOutput(
+			// Already seen in the previous poll.
+			logEvent(start.Add(30*time.Second), "Spot instance i-456 was interrupted, replacing instance"),
+			// Indexed after the previous poll.
+			logEvent(start.Add(50*time.Second), "Scale down: instance i-789 is underutilized"),
+			logEvent(start.Add(90*time.Second), "Scaling up 1 instance to maintain headroom"),
+		), nil)
+	mockClient.On("GetLogEvents", mock.Anything, logEventsInput("foo", "2025-12-31T23:57:00Z", "2026-01-01T00:03:00Z")).
+		Return(nil, errors.New("error"))
+
+	logger := zapr.NewLogger(zap.NewNop())
+	collector := NewOceanAWSAutoscalerEventsCollector(context.Background(), logger, mockClient, oceanClusters("foo"))
+
+	now := start
+	collector.now = func() time.Time { return now }
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+        # HELP spotinst_ocean_aws_spot_interruptions_total The number of spot interruptions of an ocean cluster
+        # TYPE spotinst_ocean_aws_spot_interruptions_total counter
+        spotinst_ocean_aws_spot_interruptions_total{ocean_id="foo",ocean_name="ocean-foo"} 0
+    `)))
+
+	now = start.Add(time.Minute)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+        # HELP spotinst_ocean_aws_autoscaler_last_scale_event_timestamp_seconds The time of the last scale event of an ocean cluster
+        # TYPE spotinst_ocean_aws_autoscaler_last_scale_event_timestamp_seconds gauge
+        spotinst_ocean_aws_autoscaler_last_scale_event_timestamp_seconds{ocean_id="foo",ocean_name="ocean-foo"} 1.76722562e+09
+        # HELP spotinst_ocean_aws_autoscaler_scale_events_total The number of scale up and scale down events of an ocean cluster
+        # TYPE spotinst_ocean_aws_autoscaler_scale_events_total counter
+        spotinst_ocean_aws_autoscaler_scale_events_total{direction="down",ocean_id="foo",ocean_name="ocean-foo",reason="underutilized"} 1
+        spotinst_ocean_aws_autoscaler_scale_events_total{direction="up",ocean_id="foo",ocean_name="ocean-foo",reason="pending_pods"} 1
+        # HELP spotinst_ocean_aws_spot_interruptions_total The number of spot interruptions of an ocean cluster
+        # TYPE spotinst_ocean_aws_spot_interruptions_total counter
+        spotinst_ocean_aws_spot_interruptions_total{ocean_id="foo",ocean_name="ocean-foo"} 1
+    `)))
+
+	now = start.Add(2 * time.Minute)
+
+	expected := `
+        # HELP spotinst_ocean_aws_autoscaler_last_scale_event_timestamp_seconds The time of the last scale event of an ocean cluster
+        # TYPE spotinst_ocean_aws_autoscaler_last_scale_event_timestamp_seconds gauge
+        spotinst_ocean_aws_autoscaler_last_scale_event_timestamp_seconds{ocean_id="foo",ocean_name="ocean-foo"} 1.76722569e+09
+        # HELP spotinst_ocean_aws_autoscaler_scale_events_total The number of scale up and scale down events of an ocean cluster
+        # TYPE spotinst_ocean_aws_autoscaler_scale_events_total counter
+        spotinst_ocean_aws_autoscaler_scale_events_total{direction="down",ocean_id="foo",ocean_name="ocean-foo",reason="underutilized"} 2
+        spotinst_ocean_aws_autoscaler_scale_events_total{direction="up",ocean_id="foo",ocean_name="ocean-foo",reason="headroom"} 1
+        spotinst_ocean_aws_autoscaler_scale_events_total{direction="up",ocean_id="foo",ocean_name="ocean-foo",reason="pending_pods"} 1
+        # HELP spotinst_ocean_aws_spot_interruptions_total The number of spot interruptions of an ocean cluster
+        # TYPE spotinst_ocean_aws_spot_interruptions_total counter
+        spotinst_ocean_aws_spot_interruptions_total{ocean_id="foo",ocean_name="ocean-foo"} 1
+    `
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// Counters are still exported if fetching the log events fails.
+	now = start.Add(3 * time.Minute)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestScaleEventReason(t *testing.T) {
+	assert.Equal(t, "pending_pods", scaleEventReason("Scale up due to 3 unschedulable pods"))
+	assert.Equal(t, "interruption", scaleEventReason("Scaling up to replace interrupted instance"))
+	assert.Equal(t, "capacity", scaleEventReason("Scale down to reach target capacity"))
+	assert.Equal(t, "other", scaleEventReason("Scale up"))
+}
+
+func logEventsInput(oceanID, from, to string) *aws.GetLogEventsInput {
+	return &aws.GetLogEventsInput{
+		ClusterID: spotinst.String(oceanID),
+		FromDate:  spotinst.String(from),
+		ToDate:    spotinst.String(to),
+		Limit:     spotinst.Int(logEventsLimit),
+	}
+}
+
+func logEventsOutput(events ...*aws.LogEvent) *aws.GetLogEventsOutput {
+	return &aws.GetLogEventsOutput{Events: events}
+}
+
+func logEvent(createdAt time.Time, message string) *aws.LogEvent {
+	return &aws.LogEvent{
+		CreatedAt: spotinst.Time(createdAt),
+		Message:   spotinst.String(message),
+		Severity:  spotinst.String("INFO"),
+	}
+}
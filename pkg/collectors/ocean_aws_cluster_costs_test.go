@@ -18,11 +18,11 @@ import (
 	"go.uber.org/zap"
 )
 
-type mockOceanAWSClusterCostsClient struct {
+type mockOceanClusterCostsClient struct {
 	mock.Mock
 }
 
-func (m *mockOceanAWSClusterCostsClient) GetClusterCosts(
+func (m *mockOceanClusterCostsClient) GetClusterCosts(
 	ctx context.Context,
 	input *mcs.ClusterCostInput,
 ) (*mcs.ClusterCostOutput, error) {
@@ -36,26 +36,58 @@ func (m *mockOceanAWSClusterCostsClient) GetClusterCosts(
 	return output.(*mcs.ClusterCostOutput), args.Error(1)
 }
 
+// fakeMetadataProvider is a KubernetesMetadataProvider backed by static
+// maps, keyed by "cluster/namespace" and "cluster/namespace/workload/name".
+// Owners are in the format "workload/name". Container limits are keyed by
+// "cluster/namespace/workload/name/container" and hold the CPU and memory
+// limit.
+type fakeMetadataProvider struct {
+	metadata map[string]map[string]string
+	owners   map[string]string
+	limits   map[string][2]float64
+}
+
+func (f fakeMetadataProvider) NamespaceMetadata(clusterID, namespace string) map[string]string {
+	return f.metadata[clusterID+"/"+namespace]
+}
+
+func (f fakeMetadataProvider) WorkloadMetadata(clusterID, namespace, workload, name string) map[string]string {
+	return f.metadata[clusterID+"/"+namespace+"/"+workload+"/"+name]
+}
+
+func (f fakeMetadataProvider) WorkloadOwner(clusterID, namespace, workload, name string) (string, string) {
+	owner, ownerName, _ := strings.Cut(f.owners[clusterID+"/"+namespace+"/"+workload+"/"+name], "/")
+	return owner, ownerName
+}
+
+func (f fakeMetadataProvider) ContainerLimits(clusterID, namespace, workload, name, container string) (float64, float64) {
+	limits := f.limits[clusterID+"/"+namespace+"/"+workload+"/"+name+"/"+container]
+	return limits[0], limits[1]
+}
+
 func TestOceanAWSClusterCostsCollector(t *testing.T) {
 	testCases := []struct {
-		name          string
-		client        func() OceanAWSClusterCostsClient
-		expected      string
-		labelMappings labels.Mappings
-		clusters      []*aws.Cluster
+		name               string
+		client             func() OceanClusterCostsClient
+		expected           string
+		labelResolver      labels.Resolver
+		clusterTagMappings labels.Mappings
+		metadata           KubernetesMetadataProvider
+		rollupWorkloads    bool
+		clusters           []*aws.Cluster
 	}{
 		{
 			name: "no cluster, no output",
-			client: func() OceanAWSClusterCostsClient {
-				return new(mockOceanAWSClusterCostsClient)
+			client: func() OceanClusterCostsClient {
+				return new(mockOceanClusterCostsClient)
 			},
 		},
 		{
 			name: "nonexistent cluster",
-			client: func() OceanAWSClusterCostsClient {
+			client: func() OceanClusterCostsClient {
 				input := clusterCostInput("nonexistent")
 
-				mockClient := new(mockOceanAWSClusterCostsClient)
+				mockClient := new(mockOceanClusterCostsClient)
 				mockClient.On("GetClusterCosts", mock.Anything, input).Return(nil, errors.New("nonexistent"))
 				return mockClient
 			},
@@ -63,14 +95,14 @@ func TestOceanAWSClusterCostsCollector(t *testing.T) {
 		},
 		{
 			name: "one cluster",
-			client: func() OceanAWSClusterCostsClient {
+			client: func() OceanClusterCostsClient {
 				input := clusterCostInput("foo")
 				output := clusterCostOutput(
 					200,
 					namespaceCost("foo-ns", 190, resourceCost("foo-ns", "foo-deployment", 180)),
 				)
 
-				mockClient := new(mockOceanAWSClusterCostsClient)
+				mockClient := new(mockOceanClusterCostsClient)
 				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
 				return mockClient
 			},
@@ -78,18 +110,18 @@ func TestOceanAWSClusterCostsCollector(t *testing.T) {
 			expected: `
                 # HELP spotinst_ocean_aws_cluster_cost Total cost of an ocean cluster
                 # TYPE spotinst_ocean_aws_cluster_cost gauge
-                spotinst_ocean_aws_cluster_cost{ocean_id="foo",ocean_name="ocean-foo"} 200
+                spotinst_ocean_aws_cluster_cost{cloud="aws",ocean_id="foo",ocean_name="ocean-foo"} 200
                 # HELP spotinst_ocean_aws_namespace_cost Total cost of a namespace
                 # TYPE spotinst_ocean_aws_namespace_cost gauge
-                spotinst_ocean_aws_namespace_cost{namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 190
+                spotinst_ocean_aws_namespace_cost{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 190
                 # HELP spotinst_ocean_aws_workload_cost Total cost of a workload
                 # TYPE spotinst_ocean_aws_workload_cost gauge
-                spotinst_ocean_aws_workload_cost{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 180
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 180
             `,
 		},
 		{
 			name: "propagate labels",
-			client: func() OceanAWSClusterCostsClient {
+			client: func() OceanClusterCostsClient {
 				input := clusterCostInput("foo")
 				output := clusterCostOutput(
 					200,
@@ -113,27 +145,191 @@ func TestOceanAWSClusterCostsCollector(t *testing.T) {
 					),
 				)
 
-				mockClient := new(mockOceanAWSClusterCostsClient)
+				mockClient := new(mockOceanClusterCostsClient)
 				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
 				return mockClient
 			},
 			clusters: oceanClusters("foo"),
-			labelMappings: func() labels.Mappings {
+			labelResolver: func() labels.Resolver {
 				mappings, _ := labels.ParseMappings("team,app.kubernetes.io/name=app")
+				return labels.NewResolver(mappings, nil, false)
+			}(),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_cost gauge
+                spotinst_ocean_aws_cluster_cost{cloud="aws",ocean_id="foo",ocean_name="ocean-foo"} 200
+                # HELP spotinst_ocean_aws_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_aws_namespace_cost gauge
+                spotinst_ocean_aws_namespace_cost{app="",cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team"} 190
+                spotinst_ocean_aws_namespace_cost{app="",cloud="aws",namespace="other-ns",ocean_id="foo",ocean_name="ocean-foo",team=""} 191
+                # HELP spotinst_ocean_aws_workload_cost Total cost of a workload
+                # TYPE spotinst_ocean_aws_workload_cost gauge
+                spotinst_ocean_aws_workload_cost{app="foo",cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team",workload="deployment"} 180
+                spotinst_ocean_aws_workload_cost{app="",cloud="aws",name="other-deployment",namespace="other-ns",ocean_id="foo",ocean_name="ocean-foo",team="other-team",workload="deployment"} 181
+            `,
+		},
+		{
+			name: "fall back to namespace labels and cluster tags",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(
+					200,
+					namespaceCostLabels(
+						"foo-ns",
+						190,
+						map[string]string{
+							"team": "foo-team",
+						},
+						resourceCostLabels("foo-ns", "foo-deployment", 180, map[string]string{
+							"team": "bar-team",
+						}),
+						resourceCost("foo-ns", "other-deployment", 10),
+					),
+					namespaceCost("other-ns", 10, resourceCost("other-ns", "other-deployment", 9)),
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClustersTags(map[string]string{"team": "platform"}, "foo"),
+			labelResolver: func() labels.Resolver {
+				mappings, _ := labels.ParseMappings("team")
+				return labels.NewResolver(mappings, labels.Sources{labels.SourceNamespace, labels.SourceCluster}, true)
+			}(),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_cost gauge
+                spotinst_ocean_aws_cluster_cost{cloud="aws",ocean_id="foo",ocean_name="ocean-foo"} 200
+                # HELP spotinst_ocean_aws_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_aws_namespace_cost gauge
+                spotinst_ocean_aws_namespace_cost{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team",team_source="namespace"} 190
+                spotinst_ocean_aws_namespace_cost{cloud="aws",namespace="other-ns",ocean_id="foo",ocean_name="ocean-foo",team="platform",team_source="cluster"} 10
+                # HELP spotinst_ocean_aws_workload_cost Total cost of a workload
+                # TYPE spotinst_ocean_aws_workload_cost gauge
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="bar-team",team_source="resource",workload="deployment"} 180
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="other-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team",team_source="namespace",workload="deployment"} 10
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="other-deployment",namespace="other-ns",ocean_id="foo",ocean_name="ocean-foo",team="platform",team_source="cluster",workload="deployment"} 9
+            `,
+		},
+		{
+			name: "propagate cluster tags",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(200, namespaceCost("foo-ns", 190))
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClustersTags(map[string]string{"team": "platform", "kubernetes.io/cluster/foo": "owned"}, "foo"),
+			clusterTagMappings: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team,cost-center=cost_center")
 				return mappings
 			}(),
 			expected: `
                 # HELP spotinst_ocean_aws_cluster_cost Total cost of an ocean cluster
                 # TYPE spotinst_ocean_aws_cluster_cost gauge
-                spotinst_ocean_aws_cluster_cost{ocean_id="foo",ocean_name="ocean-foo"} 200
+                spotinst_ocean_aws_cluster_cost{cloud="aws",cost_center="",ocean_id="foo",ocean_name="ocean-foo",team="platform"} 200
                 # HELP spotinst_ocean_aws_namespace_cost Total cost of a namespace
                 # TYPE spotinst_ocean_aws_namespace_cost gauge
-                spotinst_ocean_aws_namespace_cost{app="",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team"} 190
-                spotinst_ocean_aws_namespace_cost{app="",namespace="other-ns",ocean_id="foo",ocean_name="ocean-foo",team=""} 191
+                spotinst_ocean_aws_namespace_cost{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 190
+            `,
+		},
+		{
+			name: "enrich with kubernetes metadata",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(
+					200,
+					namespaceCost(
+						"foo-ns",
+						190,
+						resourceCostLabels("foo-ns", "foo-deployment", 180, map[string]string{
+							"team": "spotinst-team",
+						}),
+						resourceCost("foo-ns", "bar-deployment", 10),
+					),
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			metadata: fakeMetadataProvider{
+				metadata: map[string]map[string]string{
+					"foo/foo-ns": {"cost-center": "1234"},
+					"foo/foo-ns/deployment/foo-deployment": {
+						"team":  "kubernetes-team",
+						"owner": "alice",
+					},
+					"other/foo-ns/deployment/bar-deployment": {"owner": "bob"},
+				},
+			},
+			labelResolver: func() labels.Resolver {
+				mappings, _ := labels.ParseMappings("team,owner,cost-center=cost_center")
+				return labels.NewResolver(mappings, labels.Sources{labels.SourceNamespace}, false)
+			}(),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_cost gauge
+                spotinst_ocean_aws_cluster_cost{cloud="aws",ocean_id="foo",ocean_name="ocean-foo"} 200
+                # HELP spotinst_ocean_aws_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_aws_namespace_cost gauge
+                spotinst_ocean_aws_namespace_cost{cloud="aws",cost_center="1234",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="",team=""} 190
+                # HELP spotinst_ocean_aws_workload_cost Total cost of a workload
+                # TYPE spotinst_ocean_aws_workload_cost gauge
+                spotinst_ocean_aws_workload_cost{cloud="aws",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",team="spotinst-team",workload="deployment"} 180
+                spotinst_ocean_aws_workload_cost{cloud="aws",cost_center="1234",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="",team="",workload="deployment"} 10
+            `,
+		},
+		{
+			name: "roll up jobs into cronjobs",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(
+					200,
+					&mcs.Namespace{
+						Namespace: spotinst.String("foo-ns"),
+						Cost:      spotinst.Float64(190),
+						Jobs: []*mcs.Resource{
+							resourceCost("foo-ns", "foo-cronjob-29345678", 1),
+							resourceCost("foo-ns", "foo-cronjob-29345679", 2),
+							resourceCost("foo-ns", "bar-cronjob-abcde", 3),
+							resourceCost("foo-ns", "bar-cronjob-fghij", 4),
+							resourceCost("foo-ns", "migration-0e6b40aa-ffa2-4288-80ba-891fbad4b0ba", 5),
+							resourceCost("foo-ns", "custom-job-29345678", 6),
+						},
+					},
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			metadata: fakeMetadataProvider{
+				owners: map[string]string{
+					"foo/foo-ns/job/bar-cronjob-abcde":   "cronjob/bar-cronjob",
+					"foo/foo-ns/job/bar-cronjob-fghij":   "cronjob/bar-cronjob",
+					"foo/foo-ns/job/custom-job-29345678": "workflow/custom",
+				},
+			},
+			rollupWorkloads: true,
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_cost gauge
+                spotinst_ocean_aws_cluster_cost{cloud="aws",ocean_id="foo",ocean_name="ocean-foo"} 200
+                # HELP spotinst_ocean_aws_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_aws_namespace_cost gauge
+                spotinst_ocean_aws_namespace_cost{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 190
                 # HELP spotinst_ocean_aws_workload_cost Total cost of a workload
                 # TYPE spotinst_ocean_aws_workload_cost gauge
-                spotinst_ocean_aws_workload_cost{app="foo",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team",workload="deployment"} 180
-                spotinst_ocean_aws_workload_cost{app="",name="other-deployment",namespace="other-ns",ocean_id="foo",ocean_name="ocean-foo",team="other-team",workload="deployment"} 181
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="foo-cronjob",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="cronjob"} 3
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="bar-cronjob",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="cronjob"} 7
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="migration",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="job"} 5
+                spotinst_ocean_aws_workload_cost{cloud="aws",name="custom-job",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="job"} 6
             `,
 		},
 	}
@@ -143,7 +339,7 @@ func TestOceanAWSClusterCostsCollector(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			ctx := context.Background()
-			collector := NewOceanAWSClusterCostsCollector(ctx, logger, testCase.client(), testCase.clusters, testCase.labelMappings)
+			collector := NewOceanAWSClusterCostsCollector(ctx, logger, testCase.client(), testCase.clusters, testCase.labelResolver, testCase.clusterTagMappings, testCase.metadata, testCase.rollupWorkloads)
 
 			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
 		})
@@ -164,6 +360,23 @@ func oceanClusters(clusterIDs ...string) []*aws.Cluster {
 	return clusters
 }
 
+func oceanClustersTags(tags map[string]string, clusterIDs ...string) []*aws.Cluster {
+	clusters := oceanClusters(clusterIDs...)
+
+	awsTags := make([]*aws.Tag, 0, len(tags))
+	for key, value := range tags {
+		awsTags = append(awsTags, &aws.Tag{Key: spotinst.String(key), Value: spotinst.String(value)})
+	}
+
+	for _, cluster := range clusters {
+		cluster.Compute = &aws.Compute{
+			LaunchSpecification: &aws.LaunchSpecification{Tags: awsTags},
+		}
+	}
+
+	return clusters
+}
+
 func clusterCostInput(clusterID string) *mcs.ClusterCostInput {
 	now := time.Now()
 	firstDayOfCurrentMonth := now.AddDate(0, 0, -now.Day()+1)
@@ -237,4 +450,7 @@ func TestAggregateHighCardinalityResources(t *testing.T) {
 	}
 
 	assert.ElementsMatch(t, expected, aggregateHighCardinalityResources(resources))
+
+	// The resources are shared between collectors and must not be modified.
+	assert.Equal(t, resourceCost("foo-ns", "foo-job-27745697", 1), resources[1])
 }
@@ -0,0 +1,171 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanAWSClustersClient is the interface for listing Ocean clusters.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client.
+type OceanAWSClustersClient interface {
+	ListClusters(context.Context, *aws.ListClustersInput) (*aws.ListClustersOutput, error)
+}
+
+// OceanAWSClusterInfoCollector is a prometheus collector for the
+// configuration of Spotinst Ocean clusters on AWS.
+//
+// Unlike the other collectors, it lists the clusters on every collection in
+// order to reflect configuration changes made after the exporter was
+// started. The clusterTagMappings are used to propagate Ocean cluster tags
+// onto the cluster info metric.
+type OceanAWSClusterInfoCollector struct {
+	ctx                 context.Context
+	logger              logr.Logger
+	client              OceanAWSClustersClient
+	clusterTagMappings  labels.Mappings
+	info                *prometheus.Desc
+	minCapacity         *prometheus.Desc
+	maxCapacity         *prometheus.Desc
+	targetCapacity      *prometheus.Desc
+	autoscalerMaxCPU    *prometheus.Desc
+	autoscalerMaxMemory *prometheus.Desc
+}
+
+// NewOceanAWSClusterInfoCollector creates a new OceanAWSClusterInfoCollector
+// for collecting the configuration of all Ocean clusters of the account.
+func NewOceanAWSClusterInfoCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAWSClustersClient,
+	clusterTagMappings labels.Mappings,
+) *OceanAWSClusterInfoCollector {
+	labelNames := []string{"ocean_id", "ocean_name"}
+
+	collector := &OceanAWSClusterInfoCollector{
+		ctx:                ctx,
+		logger:             logger,
+		client:             client,
+		clusterTagMappings: clusterTagMappings,
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_info"),
+			"Information about the configuration of an ocean cluster",
+			append(
+				append(
+					labelNames,
+					"region",
+					"controller_cluster_id",
+					"spot_percentage",
+					"fallback_to_on_demand",
+					"utilize_reserved_instances",
+					"autoscaler_enabled",
+				),
+				clusterTagMappings.LabelNames()...,
+			),
+			nil,
+		),
+		minCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_capacity_min"),
+			"The configured minimum number of nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		maxCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_capacity_max"),
+			"The configured maximum number of nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		targetCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_capacity_target"),
+			"The configured target number of nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		autoscalerMaxCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_autoscaler_max_cpu"),
+			"The maximum number of CPU units the autoscaler of an ocean cluster may scale up to",
+			labelNames,
+			nil,
+		),
+		autoscalerMaxMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_autoscaler_max_memory"),
+			"The maximum number of memory units the autoscaler of an ocean cluster may scale up to",
+			labelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSClusterInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.minCapacity
+	ch <- c.maxCapacity
+	ch <- c.targetCapacity
+	ch <- c.autoscalerMaxCPU
+	ch <- c.autoscalerMaxMemory
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSClusterInfoCollector) Collect(ch chan<- prometheus.Metric) {
+	output, err := c.client.ListClusters(c.ctx, &aws.ListClustersInput{})
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean clusters")
+		return
+	}
+
+	for _, cluster := range output.Clusters {
+		c.collectClusterInfo(ch, cluster)
+	}
+}
+
+func (c *OceanAWSClusterInfoCollector) collectClusterInfo(ch chan<- prometheus.Metric, cluster *aws.Cluster) {
+	labelValues := []string{spotinst.StringValue(cluster.ID), spotinst.StringValue(cluster.Name)}
+
+	var spotPercentage, fallbackToOnDemand, utilizeReservedInstances, autoscalerEnabled string
+
+	if strategy := cluster.Strategy; strategy != nil {
+		spotPercentage = formatOptionalFloat(strategy.SpotPercentage)
+		fallbackToOnDemand = formatOptionalBool(strategy.FallbackToOnDemand)
+		utilizeReservedInstances = formatOptionalBool(strategy.UtilizeReservedInstances)
+	}
+
+	if cluster.AutoScaler != nil {
+		autoscalerEnabled = formatOptionalBool(cluster.AutoScaler.IsEnabled)
+	}
+
+	infoLabelValues := append(
+		labelValues,
+		spotinst.StringValue(cluster.Region),
+		spotinst.StringValue(cluster.ControllerClusterID),
+		spotPercentage,
+		fallbackToOnDemand,
+		utilizeReservedInstances,
+		autoscalerEnabled,
+	)
+
+	collectGaugeValue(ch, c.info, 1, append(infoLabelValues, c.clusterTagMappings.LabelValues(oceanAWSClusterTags(cluster))...))
+
+	if capacity := cluster.Capacity; capacity != nil {
+		collectOptionalGaugeValue(ch, c.minCapacity, capacity.Minimum, 1, labelValues)
+		collectOptionalGaugeValue(ch, c.maxCapacity, capacity.Maximum, 1, labelValues)
+		collectOptionalGaugeValue(ch, c.targetCapacity, capacity.Target, 1, labelValues)
+	}
+
+	if cluster.AutoScaler != nil && cluster.AutoScaler.ResourceLimits != nil {
+		limits := cluster.AutoScaler.ResourceLimits
+
+		// The limits are configured in vCPU and GiB, but exported in
+		// milli-CPU and MiB like all other metrics.
+		collectOptionalGaugeValue(ch, c.autoscalerMaxCPU, limits.MaxVCPU, 1000, labelValues)
+		collectOptionalGaugeValue(ch, c.autoscalerMaxMemory, limits.MaxMemoryGiB, 1024, labelValues)
+	}
+}
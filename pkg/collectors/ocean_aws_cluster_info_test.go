@@ -0,0 +1,152 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanAWSClustersClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanAWSClustersClient) ListClusters(
+	ctx context.Context,
+	input *aws.ListClustersInput,
+) (*aws.ListClustersOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*aws.ListClustersOutput), args.Error(1)
+}
+
+func TestOceanAWSClusterInfoCollector(t *testing.T) {
+	testCases := []struct {
+		name               string
+		client             func() OceanAWSClustersClient
+		clusterTagMappings labels.Mappings
+		expected           string
+	}{
+		{
+			name: "failing cluster list",
+			client: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "cluster without configuration",
+			client: func() OceanAWSClustersClient {
+				output := &aws.ListClustersOutput{Clusters: oceanClusters("foo")}
+
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(output, nil)
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_info Information about the configuration of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_info gauge
+                spotinst_ocean_aws_cluster_info{autoscaler_enabled="",controller_cluster_id="foo",fallback_to_on_demand="",ocean_id="foo",ocean_name="ocean-foo",region="",spot_percentage="",utilize_reserved_instances=""} 1
+            `,
+		},
+		{
+			name: "cluster with configuration",
+			client: func() OceanAWSClustersClient {
+				output := &aws.ListClustersOutput{Clusters: []*aws.Cluster{
+					{
+						ID:                  spotinst.String("foo"),
+						ControllerClusterID: spotinst.String("foo-controller"),
+						Name:                spotinst.String("ocean-foo"),
+						Region:              spotinst.String("eu-west-1"),
+						Capacity: &aws.Capacity{
+							Minimum: spotinst.Int(2),
+							Maximum: spotinst.Int(100),
+							Target:  spotinst.Int(10),
+						},
+						Strategy: &aws.Strategy{
+							SpotPercentage:           spotinst.Float64(90),
+							FallbackToOnDemand:       spotinst.Bool(true),
+							UtilizeReservedInstances: spotinst.Bool(false),
+						},
+						AutoScaler: &aws.AutoScaler{
+							IsEnabled: spotinst.Bool(true),
+							ResourceLimits: &aws.AutoScalerResourceLimits{
+								MaxVCPU:      spotinst.Int(200),
+								MaxMemoryGiB: spotinst.Int(800),
+							},
+						},
+					},
+				}}
+
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(output, nil)
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_autoscaler_max_cpu The maximum number of CPU units the autoscaler of an ocean cluster may scale up to
+                # TYPE spotinst_ocean_aws_cluster_autoscaler_max_cpu gauge
+                spotinst_ocean_aws_cluster_autoscaler_max_cpu{ocean_id="foo",ocean_name="ocean-foo"} 200000
+                # HELP spotinst_ocean_aws_cluster_autoscaler_max_memory The maximum number of memory units the autoscaler of an ocean cluster may scale up to
+                # TYPE spotinst_ocean_aws_cluster_autoscaler_max_memory gauge
+                spotinst_ocean_aws_cluster_autoscaler_max_memory{ocean_id="foo",ocean_name="ocean-foo"} 819200
+                # HELP spotinst_ocean_aws_cluster_capacity_max The configured maximum number of nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_capacity_max gauge
+                spotinst_ocean_aws_cluster_capacity_max{ocean_id="foo",ocean_name="ocean-foo"} 100
+                # HELP spotinst_ocean_aws_cluster_capacity_min The configured minimum number of nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_capacity_min gauge
+                spotinst_ocean_aws_cluster_capacity_min{ocean_id="foo",ocean_name="ocean-foo"} 2
+                # HELP spotinst_ocean_aws_cluster_capacity_target The configured target number of nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_capacity_target gauge
+                spotinst_ocean_aws_cluster_capacity_target{ocean_id="foo",ocean_name="ocean-foo"} 10
+                # HELP spotinst_ocean_aws_cluster_info Information about the configuration of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_info gauge
+                spotinst_ocean_aws_cluster_info{autoscaler_enabled="true",controller_cluster_id="foo-controller",fallback_to_on_demand="true",ocean_id="foo",ocean_name="ocean-foo",region="eu-west-1",spot_percentage="90",utilize_reserved_instances="false"} 1
+            `,
+		},
+		{
+			name: "cluster tags",
+			client: func() OceanAWSClustersClient {
+				output := &aws.ListClustersOutput{Clusters: oceanClustersTags(map[string]string{"team": "foo-team"}, "foo")}
+
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(output, nil)
+				return mockClient
+			},
+			clusterTagMappings: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team,cost-center=cost_center")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_info Information about the configuration of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_info gauge
+                spotinst_ocean_aws_cluster_info{autoscaler_enabled="",controller_cluster_id="foo",cost_center="",fallback_to_on_demand="",ocean_id="foo",ocean_name="ocean-foo",region="",spot_percentage="",team="foo-team",utilize_reserved_instances=""} 1
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSClusterInfoCollector(ctx, logger, testCase.client(), testCase.clusterTagMappings)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
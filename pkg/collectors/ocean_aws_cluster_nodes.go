@@ -0,0 +1,215 @@
+package collectors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// Normalized values of the `lifecycle` label.
+const (
+	lifecycleSpot        = "spot"
+	lifecycleOnDemand    = "on-demand"
+	lifecycleReserved    = "reserved"
+	lifecyclePreemptible = "preemptible"
+)
+
+// normalizeLifecycle converts a node lifecycle as returned by the Spotinst
+// API, e.g. "SPOT", "OD", "RI" or "PREEMPTIBLE", into the normalized value
+// of the `lifecycle` label.
+func normalizeLifecycle(lifecycle string) string {
+	switch normalized := strings.ToLower(lifecycle); normalized {
+	case "spot":
+		return lifecycleSpot
+	case "od", "ondemand", "on_demand", "on-demand":
+		return lifecycleOnDemand
+	case "ri", "reserved":
+		return lifecycleReserved
+	case "preemptible":
+		return lifecyclePreemptible
+	default:
+		return normalized
+	}
+}
+
+// OceanAWSClusterNodesClient is the interface for listing the nodes of an
+// Ocean cluster.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client.
+type OceanAWSClusterNodesClient interface {
+	ReadClusterNodes(context.Context, *aws.ReadClusterNodeInput) (*aws.ReadClusterNodeOutput, error)
+}
+
+// nodeGroupKey identifies a group of nodes of a cluster sharing the same
+// instance type, availability zone, lifecycle and launch spec.
+type nodeGroupKey struct {
+	instanceType     string
+	availabilityZone string
+	lifecycle        string
+	launchSpecID     string
+	launchSpecName   string
+}
+
+// nodeGroup holds the aggregated resources of a group of nodes.
+type nodeGroup struct {
+	nodes             float64
+	allocatableCPU    float64
+	usedCPU           float64
+	allocatableMemory float64
+	usedMemory        float64
+}
+
+// OceanAWSClusterNodesCollector is a prometheus collector for the nodes
+// launched by Spotinst Ocean clusters on AWS.
+type OceanAWSClusterNodesCollector struct {
+	ctx               context.Context
+	logger            logr.Logger
+	client            OceanAWSClusterNodesClient
+	clusters          []*aws.Cluster
+	nodes             *prometheus.Desc
+	allocatableCPU    *prometheus.Desc
+	usedCPU           *prometheus.Desc
+	allocatableMemory *prometheus.Desc
+	usedMemory        *prometheus.Desc
+}
+
+// NewOceanAWSClusterNodesCollector creates a new OceanAWSClusterNodesCollector
+// for collecting the nodes of the provided list of Ocean clusters.
+func NewOceanAWSClusterNodesCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAWSClusterNodesClient,
+	clusters []*aws.Cluster,
+) *OceanAWSClusterNodesCollector {
+	labelNames := []string{
+		"ocean_id",
+		"ocean_name",
+		"instance_type",
+		"availability_zone",
+		"lifecycle",
+		"launch_spec_id",
+		"launch_spec_name",
+	}
+
+	collector := &OceanAWSClusterNodesCollector{
+		ctx:      ctx,
+		logger:   logger,
+		client:   client,
+		clusters: clusters,
+		nodes: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_nodes"),
+			"The number of nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		allocatableCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_nodes_cpu_allocatable"),
+			"The number of CPU units allocatable on the nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		usedCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_nodes_cpu_used"),
+			"The number of CPU units requested by workloads on the nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		allocatableMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_nodes_memory_allocatable"),
+			"The number of memory units allocatable on the nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		usedMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_nodes_memory_used"),
+			"The number of memory units requested by workloads on the nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSClusterNodesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.nodes
+	ch <- c.allocatableCPU
+	ch <- c.usedCPU
+	ch <- c.allocatableMemory
+	ch <- c.usedMemory
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSClusterNodesCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, cluster := range c.clusters {
+		output, err := c.client.ReadClusterNodes(c.ctx, &aws.ReadClusterNodeInput{
+			ClusterID: cluster.ID,
+		})
+		if err != nil {
+			clusterID := spotinst.StringValue(cluster.ID)
+			c.logger.Error(err, "failed to read cluster nodes", "ocean_id", clusterID)
+			continue
+		}
+
+		c.collectNodes(ch, output.ClusterNode, cluster)
+	}
+}
+
+func (c *OceanAWSClusterNodesCollector) collectNodes(
+	ch chan<- prometheus.Metric,
+	nodes []*aws.ClusterNodes,
+	cluster *aws.Cluster,
+) {
+	for key, group := range groupClusterNodes(nodes) {
+		labelValues := []string{
+			spotinst.StringValue(cluster.ID),
+			spotinst.StringValue(cluster.Name),
+			key.instanceType,
+			key.availabilityZone,
+			key.lifecycle,
+			key.launchSpecID,
+			key.launchSpecName,
+		}
+
+		collectGaugeValue(ch, c.nodes, group.nodes, labelValues)
+		collectGaugeValue(ch, c.allocatableCPU, group.allocatableCPU, labelValues)
+		collectGaugeValue(ch, c.usedCPU, group.usedCPU, labelValues)
+		collectGaugeValue(ch, c.allocatableMemory, group.allocatableMemory, labelValues)
+		collectGaugeValue(ch, c.usedMemory, group.usedMemory, labelValues)
+	}
+}
+
+// groupClusterNodes aggregates the resources of nodes sharing the same
+// instance type, availability zone, lifecycle and launch spec.
+func groupClusterNodes(nodes []*aws.ClusterNodes) map[nodeGroupKey]*nodeGroup {
+	groups := make(map[nodeGroupKey]*nodeGroup)
+
+	for _, node := range nodes {
+		key := nodeGroupKey{
+			instanceType:     spotinst.StringValue(node.InstanceType),
+			availabilityZone: spotinst.StringValue(node.AvailabilityZone),
+			lifecycle:        normalizeLifecycle(spotinst.StringValue(node.LifeCycle)),
+			launchSpecID:     spotinst.StringValue(node.LaunchSpecId),
+			launchSpecName:   spotinst.StringValue(node.LaunchSpecName),
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &nodeGroup{}
+			groups[key] = group
+		}
+
+		group.nodes++
+		group.allocatableCPU += float64(spotinst.IntValue(node.AllocatableMilliCpu))
+		group.usedCPU += float64(spotinst.IntValue(node.WorkloadRequestedMilliCpu))
+		group.allocatableMemory += spotinst.Float64Value(node.AllocatableMemoryInMiB)
+		group.usedMemory += float64(spotinst.IntValue(node.WorkloadRequestedMemoryInMiB))
+	}
+
+	return groups
+}
@@ -0,0 +1,149 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanAWSClusterNodesClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanAWSClusterNodesClient) ReadClusterNodes(
+	ctx context.Context,
+	input *aws.ReadClusterNodeInput,
+) (*aws.ReadClusterNodeOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*aws.ReadClusterNodeOutput), args.Error(1)
+}
+
+func TestOceanAWSClusterNodesCollector(t *testing.T) {
+	testCases := []struct {
+		name     string
+		client   func() OceanAWSClusterNodesClient
+		expected string
+		clusters []*aws.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			client: func() OceanAWSClusterNodesClient {
+				return new(mockOceanAWSClusterNodesClient)
+			},
+		},
+		{
+			name: "nonexistent cluster",
+			client: func() OceanAWSClusterNodesClient {
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("nonexistent")).Return(nil, errors.New("nonexistent"))
+				return mockClient
+			},
+			clusters: oceanClusters("nonexistent"),
+		},
+		{
+			name: "nodes grouped by instance type, zone, lifecycle and launch spec",
+			client: func() OceanAWSClusterNodesClient {
+				output := clusterNodesOutput(
+					clusterNode("m5.large", "eu-west-1a", "SPOT", "ols-1", "default", 1930, 900, 7000, 4000),
+					clusterNode("m5.large", "eu-west-1a", "SPOT", "ols-1", "default", 1930, 300, 7000, 1000),
+					clusterNode("m5.large", "eu-west-1a", "OD", "ols-1", "default", 1930, 1500, 7000, 6000),
+					clusterNode("c5.xlarge", "eu-west-1b", "SPOT", "ols-2", "compute", 3920, 3000, 6500, 2000),
+				)
+
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_nodes The number of nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_nodes gauge
+                spotinst_ocean_aws_cluster_nodes{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_cluster_nodes{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 2
+                spotinst_ocean_aws_cluster_nodes{availability_zone="eu-west-1b",instance_type="c5.xlarge",launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_aws_cluster_nodes_cpu_allocatable The number of CPU units allocatable on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_nodes_cpu_allocatable gauge
+                spotinst_ocean_aws_cluster_nodes_cpu_allocatable{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 1930
+                spotinst_ocean_aws_cluster_nodes_cpu_allocatable{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 3860
+                spotinst_ocean_aws_cluster_nodes_cpu_allocatable{availability_zone="eu-west-1b",instance_type="c5.xlarge",launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 3920
+                # HELP spotinst_ocean_aws_cluster_nodes_cpu_used The number of CPU units requested by workloads on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_nodes_cpu_used gauge
+                spotinst_ocean_aws_cluster_nodes_cpu_used{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 1500
+                spotinst_ocean_aws_cluster_nodes_cpu_used{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 1200
+                spotinst_ocean_aws_cluster_nodes_cpu_used{availability_zone="eu-west-1b",instance_type="c5.xlarge",launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 3000
+                # HELP spotinst_ocean_aws_cluster_nodes_memory_allocatable The number of memory units allocatable on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_nodes_memory_allocatable gauge
+                spotinst_ocean_aws_cluster_nodes_memory_allocatable{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 7000
+                spotinst_ocean_aws_cluster_nodes_memory_allocatable{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 14000
+                spotinst_ocean_aws_cluster_nodes_memory_allocatable{availability_zone="eu-west-1b",instance_type="c5.xlarge",launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 6500
+                # HELP spotinst_ocean_aws_cluster_nodes_memory_used The number of memory units requested by workloads on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_nodes_memory_used gauge
+                spotinst_ocean_aws_cluster_nodes_memory_used{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 6000
+                spotinst_ocean_aws_cluster_nodes_memory_used{availability_zone="eu-west-1a",instance_type="m5.large",launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 5000
+                spotinst_ocean_aws_cluster_nodes_memory_used{availability_zone="eu-west-1b",instance_type="c5.xlarge",launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 2000
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSClusterNodesCollector(ctx, logger, testCase.client(), testCase.clusters)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func TestNormalizeLifecycle(t *testing.T) {
+	assert.Equal(t, "spot", normalizeLifecycle("SPOT"))
+	assert.Equal(t, "on-demand", normalizeLifecycle("OD"))
+	assert.Equal(t, "on-demand", normalizeLifecycle("on_demand"))
+	assert.Equal(t, "reserved", normalizeLifecycle("RI"))
+	assert.Equal(t, "preemptible", normalizeLifecycle("PREEMPTIBLE"))
+	assert.Equal(t, "unknown", normalizeLifecycle("Unknown"))
+}
+
+func clusterNodesInput(oceanID string) *aws.ReadClusterNodeInput {
+	return &aws.ReadClusterNodeInput{ClusterID: spotinst.String(oceanID)}
+}
+
+func clusterNodesOutput(nodes ...*aws.ClusterNodes) *aws.ReadClusterNodeOutput {
+	return &aws.ReadClusterNodeOutput{ClusterNode: nodes}
+}
+
+func clusterNode(
+	instanceType, zone, lifecycle, launchSpecID, launchSpecName string,
+	allocatableCPU, usedCPU int,
+	allocatableMemory float64,
+	usedMemory int,
+) *aws.ClusterNodes {
+	return &aws.ClusterNodes{
+		InstanceType:                 spotinst.String(instanceType),
+		AvailabilityZone:             spotinst.String(zone),
+		LifeCycle:                    spotinst.String(lifecycle),
+		LaunchSpecId:                 spotinst.String(launchSpecID),
+		LaunchSpecName:               spotinst.String(launchSpecName),
+		AllocatableMilliCpu:          spotinst.Int(allocatableCPU),
+		WorkloadRequestedMilliCpu:    spotinst.Int(usedCPU),
+		AllocatableMemoryInMiB:       spotinst.Float64(allocatableMemory),
+		WorkloadRequestedMemoryInMiB: spotinst.Int(usedMemory),
+	}
+}
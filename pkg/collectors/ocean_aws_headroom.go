@@ -0,0 +1,330 @@
+package collectors
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+var (
+	pendingPodsRegex       = regexp.MustCompile(`(?i)\b(\d+) (?:pending pods?|pods? (?:(?:is|are) )?pending)\b`)
+	unschedulablePodsRegex = regexp.MustCompile(`(?i)\b(\d+) (?:unschedulable pods?|pods? (?:(?:is|are) )?(?:unschedulable|not schedulable))\b`)
+)
+
+// pendingPodsWindow is the time range of log events which is searched for the
+// number of pending and unschedulable pods last reported by the autoscaler.
+const pendingPodsWindow = 10 * time.Minute
+
+// latestPodCounts returns the number of pending and unschedulable pods
+// reported by the latest log events mentioning them. Counts which are not
+// mentioned by any of the events are 0.
+func latestPodCounts(events []*aws.LogEvent) (float64, float64) {
+	var (
+		pending, unschedulable     float64
+		pendingAt, unschedulableAt time.Time
+	)
+
+	for _, event := range events {
+		createdAt := spotinst.TimeValue(event.CreatedAt)
+		message := spotinst.StringValue(event.Message)
+
+		if count, ok := parsePodCount(pendingPodsRegex, message); ok && !createdAt.Before(pendingAt) {
+			pending, pendingAt = count, createdAt
+		}
+
+		if count, ok := parsePodCount(unschedulablePodsRegex, message); ok && !createdAt.Before(unschedulableAt) {
+			unschedulable, unschedulableAt = count, createdAt
+		}
+	}
+
+	return pending, unschedulable
+}
+
+// parsePodCount returns the number of pods captured by regex from a log
+// message, if any.
+func parsePodCount(regex *regexp.Regexp, message string) (float64, bool) {
+	match := regex.FindStringSubmatch(message)
+	if match == nil {
+		return 0, false
+	}
+
+	count, err := strconv.ParseFloat(match[1], 64)
+
+	return count, err == nil
+}
+
+// headroom holds the resources reserved as headroom on a set of nodes.
+type headroom struct {
+	cpu    float64
+	memory float64
+	gpu    float64
+}
+
+// add adds the headroom reserved on a node.
+func (h *headroom) add(node *aws.ClusterNodes) {
+	h.cpu += float64(spotinst.IntValue(node.HeadroomRequestedMilliCpu))
+	h.memory += float64(spotinst.IntValue(node.HeadroomRequestedMemoryInMiB))
+	h.gpu += float64(spotinst.IntValue(node.HeadroomRequestedGpu))
+}
+
+// OceanAWSHeadroomCollector is a prometheus collector for the configured and
+// the actually reserved headroom of Spotinst Ocean clusters on AWS, as well as
+// the pods the autoscaler reports as pending or unschedulable.
+//
+// The configured headroom is read from the clusters listed on every
+// collection, in order to reflect configuration changes made after the
+// exporter was started. The pod counts are derived from the cluster log
+// events, as the Spotinst API does not report them otherwise.
+type OceanAWSHeadroomCollector struct {
+	ctx                      context.Context
+	logger                   logr.Logger
+	clustersClient           OceanAWSClustersClient
+	nodesClient              OceanAWSClusterNodesClient
+	logEventsClient          OceanAWSLogEventsClient
+	clusters                 []*aws.Cluster
+	now                      func() time.Time
+	configuredCPU            *prometheus.Desc
+	configuredMemory         *prometheus.Desc
+	configuredGPU            *prometheus.Desc
+	reservedCPU              *prometheus.Desc
+	reservedMemory           *prometheus.Desc
+	reservedGPU              *prometheus.Desc
+	launchSpecReservedCPU    *prometheus.Desc
+	launchSpecReservedMemory *prometheus.Desc
+	launchSpecReservedGPU    *prometheus.Desc
+	pendingPods              *prometheus.Desc
+	unschedulablePods        *prometheus.Desc
+}
+
+// NewOceanAWSHeadroomCollector creates a new OceanAWSHeadroomCollector for
+// collecting the headroom of the provided list of Ocean clusters.
+func NewOceanAWSHeadroomCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	clustersClient OceanAWSClustersClient,
+	nodesClient OceanAWSClusterNodesClient,
+	logEventsClient OceanAWSLogEventsClient,
+	clusters []*aws.Cluster,
+) *OceanAWSHeadroomCollector {
+	clusterLabelNames := []string{"ocean_id", "ocean_name"}
+	launchSpecLabelNames := []string{"ocean_id", "ocean_name", "launch_spec_id", "launch_spec_name"}
+
+	collector := &OceanAWSHeadroomCollector{
+		ctx:             ctx,
+		logger:          logger,
+		clustersClient:  clustersClient,
+		nodesClient:     nodesClient,
+		logEventsClient: logEventsClient,
+		clusters:        clusters,
+		now:             time.Now,
+		configuredCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_headroom_cpu_configured"),
+			"The number of CPU units configured as headroom of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		configuredMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_headroom_memory_configured"),
+			"The number of memory units configured as headroom of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		configuredGPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_headroom_gpu_configured"),
+			"The number of GPUs configured as headroom of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		reservedCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_headroom_cpu_reserved"),
+			"The number of CPU units reserved as headroom on the nodes of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		reservedMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_headroom_memory_reserved"),
+			"The number of memory units reserved as headroom on the nodes of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		reservedGPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_headroom_gpu_reserved"),
+			"The number of GPUs reserved as headroom on the nodes of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		launchSpecReservedCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_headroom_cpu_reserved"),
+			"The number of CPU units reserved as headroom on the nodes of a launch spec",
+			launchSpecLabelNames,
+			nil,
+		),
+		launchSpecReservedMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_headroom_memory_reserved"),
+			"The number of memory units reserved as headroom on the nodes of a launch spec",
+			launchSpecLabelNames,
+			nil,
+		),
+		launchSpecReservedGPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_headroom_gpu_reserved"),
+			"The number of GPUs reserved as headroom on the nodes of a launch spec",
+			launchSpecLabelNames,
+			nil,
+		),
+		pendingPods: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_pending_pods"),
+			"The number of pending pods last reported by the autoscaler of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		unschedulablePods: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_unschedulable_pods"),
+			"The number of unschedulable pods last reported by the autoscaler of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSHeadroomCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.configuredCPU
+	ch <- c.configuredMemory
+	ch <- c.configuredGPU
+	ch <- c.reservedCPU
+	ch <- c.reservedMemory
+	ch <- c.reservedGPU
+	ch <- c.launchSpecReservedCPU
+	ch <- c.launchSpecReservedMemory
+	ch <- c.launchSpecReservedGPU
+	ch <- c.pendingPods
+	ch <- c.unschedulablePods
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSHeadroomCollector) Collect(ch chan<- prometheus.Metric) {
+	headrooms := c.listConfiguredHeadrooms()
+	now := c.now().UTC()
+
+	for _, cluster := range c.clusters {
+		clusterID := spotinst.StringValue(cluster.ID)
+		labelValues := []string{clusterID, spotinst.StringValue(cluster.Name)}
+
+		if configured, ok := headrooms[clusterID]; ok {
+			c.collectConfiguredHeadroom(ch, configured, labelValues)
+		}
+
+		c.collectPendingPods(ch, cluster, labelValues, now)
+
+		output, err := c.nodesClient.ReadClusterNodes(c.ctx, &aws.ReadClusterNodeInput{
+			ClusterID: cluster.ID,
+		})
+		if err != nil {
+			c.logger.Error(err, "failed to read cluster nodes", "ocean_id", clusterID)
+			continue
+		}
+
+		c.collectReservedHeadroom(ch, output.ClusterNode, cluster)
+	}
+}
+
+// listConfiguredHeadrooms lists the clusters and returns their configured
+// headroom by cluster ID. Clusters without a configured headroom are omitted.
+func (c *OceanAWSHeadroomCollector) listConfiguredHeadrooms() map[string]*aws.AutoScalerHeadroom {
+	output, err := c.clustersClient.ListClusters(c.ctx, &aws.ListClustersInput{})
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean clusters")
+		return nil
+	}
+
+	headrooms := make(map[string]*aws.AutoScalerHeadroom, len(output.Clusters))
+
+	for _, cluster := range output.Clusters {
+		if cluster.AutoScaler != nil && cluster.AutoScaler.Headroom != nil {
+			headrooms[spotinst.StringValue(cluster.ID)] = cluster.AutoScaler.Headroom
+		}
+	}
+
+	return headrooms
+}
+
+func (c *OceanAWSHeadroomCollector) collectConfiguredHeadroom(
+	ch chan<- prometheus.Metric,
+	configured *aws.AutoScalerHeadroom,
+	labelValues []string,
+) {
+	units := float64(spotinst.IntValue(configured.NumOfUnits))
+
+	collectGaugeValue(ch, c.configuredCPU, units*float64(spotinst.IntValue(configured.CPUPerUnit)), labelValues)
+	collectGaugeValue(ch, c.configuredMemory, units*float64(spotinst.IntValue(configured.MemoryPerUnit)), labelValues)
+	collectGaugeValue(ch, c.configuredGPU, units*float64(spotinst.IntValue(configured.GPUPerUnit)), labelValues)
+}
+
+func (c *OceanAWSHeadroomCollector) collectPendingPods(
+	ch chan<- prometheus.Metric,
+	cluster *aws.Cluster,
+	labelValues []string,
+	now time.Time,
+) {
+	output, err := c.logEventsClient.GetLogEvents(c.ctx, &aws.GetLogEventsInput{
+		ClusterID: cluster.ID,
+		FromDate:  spotinst.String(now.Add(-pendingPodsWindow).Format(time.RFC3339)),
+		ToDate:    spotinst.String(now.Format(time.RFC3339)),
+		Limit:     spotinst.Int(logEventsLimit),
+	})
+	if err != nil {
+		c.logger.Error(err, "failed to fetch log events", "ocean_id", spotinst.StringValue(cluster.ID))
+		return
+	}
+
+	pending, unschedulable := latestPodCounts(output.Events)
+
+	collectGaugeValue(ch, c.pendingPods, pending, labelValues)
+	collectGaugeValue(ch, c.unschedulablePods, unschedulable, labelValues)
+}
+
+func (c *OceanAWSHeadroomCollector) collectReservedHeadroom(
+	ch chan<- prometheus.Metric,
+	nodes []*aws.ClusterNodes,
+	cluster *aws.Cluster,
+) {
+	var clusterHeadroom headroom
+
+	launchSpecHeadrooms := make(map[launchSpecKey]*headroom)
+
+	for _, node := range nodes {
+		clusterHeadroom.add(node)
+
+		key := launchSpecKey{id: spotinst.StringValue(node.LaunchSpecId), name: spotinst.StringValue(node.LaunchSpecName)}
+
+		launchSpecHeadroom, ok := launchSpecHeadrooms[key]
+		if !ok {
+			launchSpecHeadroom = &headroom{}
+			launchSpecHeadrooms[key] = launchSpecHeadroom
+		}
+
+		launchSpecHeadroom.add(node)
+	}
+
+	labelValues := []string{spotinst.StringValue(cluster.ID), spotinst.StringValue(cluster.Name)}
+
+	collectGaugeValue(ch, c.reservedCPU, clusterHeadroom.cpu, labelValues)
+	collectGaugeValue(ch, c.reservedMemory, clusterHeadroom.memory, labelValues)
+	collectGaugeValue(ch, c.reservedGPU, clusterHeadroom.gpu, labelValues)
+
+	for key, launchSpecHeadroom := range launchSpecHeadrooms {
+		specLabelValues := append(labelValues, key.id, key.name)
+
+		collectGaugeValue(ch, c.launchSpecReservedCPU, launchSpecHeadroom.cpu, specLabelValues)
+		collectGaugeValue(ch, c.launchSpecReservedMemory, launchSpecHeadroom.memory, specLabelValues)
+		collectGaugeValue(ch, c.launchSpecReservedGPU, launchSpecHeadroom.gpu, specLabelValues)
+	}
+}
@@ -0,0 +1,236 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestOceanAWSHeadroomCollector(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	eventsInput := logEventsInput("foo", "2024-05-01T11:50:00Z", "2024-05-01T12:00:00Z")
+
+	// The headroom was configured after the exporter was started.
+	clustersWithHeadroom := &aws.ListClustersOutput{Clusters: oceanClusters("foo")}
+	clustersWithHeadroom.Clusters[0].AutoScaler = &aws.AutoScaler{
+		Headroom: &aws.AutoScalerHeadroom{
+			CPUPerUnit:    spotinst.Int(500),
+			MemoryPerUnit: spotinst.Int(1024),
+			NumOfUnits:    spotinst.Int(4),
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		clustersClient  func() OceanAWSClustersClient
+		nodesClient     func() OceanAWSClusterNodesClient
+		logEventsClient func() OceanAWSLogEventsClient
+		expected        string
+		clusters        []*aws.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			clustersClient: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).
+					Return(&aws.ListClustersOutput{}, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				return new(mockOceanAWSClusterNodesClient)
+			},
+			logEventsClient: func() OceanAWSLogEventsClient {
+				return new(mockOceanAWSLogEventsClient)
+			},
+		},
+		{
+			name: "failing clients",
+			clustersClient: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			logEventsClient: func() OceanAWSLogEventsClient {
+				mockClient := new(mockOceanAWSLogEventsClient)
+				mockClient.On("GetLogEvents", mock.Anything, eventsInput).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+		},
+		{
+			name: "configured headroom",
+			clustersClient: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(clustersWithHeadroom, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			logEventsClient: func() OceanAWSLogEventsClient {
+				mockClient := new(mockOceanAWSLogEventsClient)
+				mockClient.On("GetLogEvents", mock.Anything, eventsInput).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_headroom_cpu_configured The number of CPU units configured as headroom of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_headroom_cpu_configured gauge
+                spotinst_ocean_aws_cluster_headroom_cpu_configured{ocean_id="foo",ocean_name="ocean-foo"} 2000
+                # HELP spotinst_ocean_aws_cluster_headroom_gpu_configured The number of GPUs configured as headroom of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_headroom_gpu_configured gauge
+                spotinst_ocean_aws_cluster_headroom_gpu_configured{ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_cluster_headroom_memory_configured The number of memory units configured as headroom of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_headroom_memory_configured gauge
+                spotinst_ocean_aws_cluster_headroom_memory_configured{ocean_id="foo",ocean_name="ocean-foo"} 4096
+            `,
+		},
+		{
+			name: "reserved headroom and pending pods",
+			clustersClient: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).
+					Return(&aws.ListClustersOutput{Clusters: oceanClusters("foo")}, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				output := clusterNodesOutput(
+					headroomNode("ols-1", "default", 1000, 2048, 0),
+					headroomNode("ols-1", "default", 500, 1024, 0),
+					headroomNode("ols-2", "gpu", 0, 0, 1),
+				)
+
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			logEventsClient: func() OceanAWSLogEventsClient {
+				output := logEventsOutput(
+					logEvent(now.Add(-2*time.Minute), "Scale up: 5 pods are pending, launching 2 instances"),
+					logEvent(now.Add(-8*time.Minute), "Scale up: 7 pending pods, 2 unschedulable pods"),
+					logEvent(now.Add(-time.Minute), "Instance i-123 was launched"),
+				)
+
+				mockClient := new(mockOceanAWSLogEventsClient)
+				mockClient.On("GetLogEvents", mock.Anything, eventsInput).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_headroom_cpu_reserved The number of CPU units reserved as headroom on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_headroom_cpu_reserved gauge
+                spotinst_ocean_aws_cluster_headroom_cpu_reserved{ocean_id="foo",ocean_name="ocean-foo"} 1500
+                # HELP spotinst_ocean_aws_cluster_headroom_gpu_reserved The number of GPUs reserved as headroom on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_headroom_gpu_reserved gauge
+                spotinst_ocean_aws_cluster_headroom_gpu_reserved{ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_aws_cluster_headroom_memory_reserved The number of memory units reserved as headroom on the nodes of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_headroom_memory_reserved gauge
+                spotinst_ocean_aws_cluster_headroom_memory_reserved{ocean_id="foo",ocean_name="ocean-foo"} 3072
+                # HELP spotinst_ocean_aws_cluster_pending_pods The number of pending pods last reported by the autoscaler of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_pending_pods gauge
+                spotinst_ocean_aws_cluster_pending_pods{ocean_id="foo",ocean_name="ocean-foo"} 5
+                # HELP spotinst_ocean_aws_cluster_unschedulable_pods The number of unschedulable pods last reported by the autoscaler of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_unschedulable_pods gauge
+                spotinst_ocean_aws_cluster_unschedulable_pods{ocean_id="foo",ocean_name="ocean-foo"} 2
+                # HELP spotinst_ocean_aws_launch_spec_headroom_cpu_reserved The number of CPU units reserved as headroom on the nodes of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_cpu_reserved gauge
+                spotinst_ocean_aws_launch_spec_headroom_cpu_reserved{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 1500
+                spotinst_ocean_aws_launch_spec_headroom_cpu_reserved{launch_spec_id="ols-2",launch_spec_name="gpu",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_headroom_gpu_reserved The number of GPUs reserved as headroom on the nodes of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_gpu_reserved gauge
+                spotinst_ocean_aws_launch_spec_headroom_gpu_reserved{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 0
+                spotinst_ocean_aws_launch_spec_headroom_gpu_reserved{launch_spec_id="ols-2",launch_spec_name="gpu",ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_aws_launch_spec_headroom_memory_reserved The number of memory units reserved as headroom on the nodes of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_memory_reserved gauge
+                spotinst_ocean_aws_launch_spec_headroom_memory_reserved{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 3072
+                spotinst_ocean_aws_launch_spec_headroom_memory_reserved{launch_spec_id="ols-2",launch_spec_name="gpu",ocean_id="foo",ocean_name="ocean-foo"} 0
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSHeadroomCollector(
+				ctx,
+				logger,
+				testCase.clustersClient(),
+				testCase.nodesClient(),
+				testCase.logEventsClient(),
+				testCase.clusters,
+			)
+			collector.now = func() time.Time { return now }
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func TestLatestPodCounts(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		events        []*aws.LogEvent
+		pending       float64
+		unschedulable float64
+	}{
+		{
+			name: "no events",
+		},
+		{
+			name: "unrelated events",
+			events: []*aws.LogEvent{
+				logEvent(now, "Scale down: node i-123 is underutilized"),
+			},
+		},
+		{
+			name: "latest counts win",
+			events: []*aws.LogEvent{
+				logEvent(now, "1 pod is pending"),
+				logEvent(now.Add(-time.Minute), "3 pending pods, 2 pods are not schedulable due to taints"),
+				logEvent(now.Add(-2*time.Minute), "4 pods are unschedulable"),
+			},
+			pending:       1,
+			unschedulable: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pending, unschedulable := latestPodCounts(testCase.events)
+
+			assert.Equal(t, testCase.pending, pending)
+			assert.Equal(t, testCase.unschedulable, unschedulable)
+		})
+	}
+}
+
+func headroomNode(launchSpecID, launchSpecName string, cpu, memory, gpu int) *aws.ClusterNodes {
+	return &aws.ClusterNodes{
+		LaunchSpecId:                 spotinst.String(launchSpecID),
+		LaunchSpecName:               spotinst.String(launchSpecName),
+		HeadroomRequestedMilliCpu:    spotinst.Int(cpu),
+		HeadroomRequestedMemoryInMiB: spotinst.Int(memory),
+		HeadroomRequestedGpu:         spotinst.Int(gpu),
+	}
+}
@@ -0,0 +1,258 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// lifecycleMix holds the number of instances and the allocatable CPU per
+// lifecycle of a group of nodes.
+type lifecycleMix struct {
+	instances map[string]float64
+	cpu       map[string]float64
+	totalCPU  float64
+}
+
+// newLifecycleMix creates a new lifecycleMix. Spot and on-demand instances
+// are always present, so that falling back to on-demand entirely results in
+// an explicit zero for spot instances.
+func newLifecycleMix() *lifecycleMix {
+	return &lifecycleMix{
+		instances: map[string]float64{lifecycleSpot: 0, lifecycleOnDemand: 0},
+		cpu:       map[string]float64{lifecycleSpot: 0, lifecycleOnDemand: 0},
+	}
+}
+
+func (m *lifecycleMix) add(node *aws.ClusterNodes) {
+	lifecycle := normalizeLifecycle(spotinst.StringValue(node.LifeCycle))
+	cpu := float64(spotinst.IntValue(node.AllocatableMilliCpu))
+
+	m.instances[lifecycle]++
+	m.cpu[lifecycle] += cpu
+	m.totalCPU += cpu
+}
+
+// launchSpecKey identifies a launch spec of a cluster.
+type launchSpecKey struct {
+	id   string
+	name string
+}
+
+// OceanAWSInstanceMixCollector is a prometheus collector for the mix of spot,
+// on-demand and reserved instances of Spotinst Ocean clusters on AWS and
+// their launch specs.
+//
+// The spot percentage targets are read from the clusters listed on every
+// collection, in order to reflect configuration changes made after the
+// exporter was started.
+type OceanAWSInstanceMixCollector struct {
+	ctx                  context.Context
+	logger               logr.Logger
+	clustersClient       OceanAWSClustersClient
+	nodesClient          OceanAWSClusterNodesClient
+	launchSpecsClient    OceanAWSLaunchSpecsClient
+	clusters             []*aws.Cluster
+	clusterInstances     *prometheus.Desc
+	clusterCPUShare      *prometheus.Desc
+	clusterSpotTarget    *prometheus.Desc
+	launchSpecInstances  *prometheus.Desc
+	launchSpecCPUShare   *prometheus.Desc
+	launchSpecSpotTarget *prometheus.Desc
+}
+
+// NewOceanAWSInstanceMixCollector creates a new OceanAWSInstanceMixCollector
+// for collecting the instance mix of the provided list of Ocean clusters.
+func NewOceanAWSInstanceMixCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	clustersClient OceanAWSClustersClient,
+	nodesClient OceanAWSClusterNodesClient,
+	launchSpecsClient OceanAWSLaunchSpecsClient,
+	clusters []*aws.Cluster,
+) *OceanAWSInstanceMixCollector {
+	clusterLabelNames := []string{"ocean_id", "ocean_name"}
+	launchSpecLabelNames := []string{"ocean_id", "ocean_name", "launch_spec_id", "launch_spec_name"}
+
+	collector := &OceanAWSInstanceMixCollector{
+		ctx:               ctx,
+		logger:            logger,
+		clustersClient:    clustersClient,
+		nodesClient:       nodesClient,
+		launchSpecsClient: launchSpecsClient,
+		clusters:          clusters,
+		clusterInstances: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_instances"),
+			"The number of instances of an ocean cluster per lifecycle",
+			append(clusterLabelNames, "lifecycle"),
+			nil,
+		),
+		clusterCPUShare: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_cpu_share"),
+			"The share of allocatable CPU units of an ocean cluster per lifecycle",
+			append(clusterLabelNames, "lifecycle"),
+			nil,
+		),
+		clusterSpotTarget: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_spot_percentage_target"),
+			"The configured percentage of spot instances of an ocean cluster",
+			clusterLabelNames,
+			nil,
+		),
+		launchSpecInstances: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_instances"),
+			"The number of instances of a launch spec per lifecycle",
+			append(launchSpecLabelNames, "lifecycle"),
+			nil,
+		),
+		launchSpecCPUShare: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_cpu_share"),
+			"The share of allocatable CPU units of a launch spec per lifecycle",
+			append(launchSpecLabelNames, "lifecycle"),
+			nil,
+		),
+		launchSpecSpotTarget: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_spot_percentage_target"),
+			"The configured percentage of spot instances of a launch spec",
+			launchSpecLabelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSInstanceMixCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clusterInstances
+	ch <- c.clusterCPUShare
+	ch <- c.clusterSpotTarget
+	ch <- c.launchSpecInstances
+	ch <- c.launchSpecCPUShare
+	ch <- c.launchSpecSpotTarget
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSInstanceMixCollector) Collect(ch chan<- prometheus.Metric) {
+	spotTargets := c.listSpotTargets()
+
+	for _, cluster := range c.clusters {
+		clusterID := spotinst.StringValue(cluster.ID)
+		labelValues := []string{clusterID, spotinst.StringValue(cluster.Name)}
+
+		if spotTarget, ok := spotTargets[clusterID]; ok {
+			collectGaugeValue(ch, c.clusterSpotTarget, spotTarget, labelValues)
+		}
+
+		launchSpecs, err := c.launchSpecsClient.ListLaunchSpecs(c.ctx, &aws.ListLaunchSpecsInput{
+			OceanID: cluster.ID,
+		})
+		if err != nil {
+			c.logger.Error(err, "failed to list launch specs", "ocean_id", clusterID)
+		} else {
+			c.collectLaunchSpecTargets(ch, launchSpecs.LaunchSpecs, cluster)
+		}
+
+		nodes, err := c.nodesClient.ReadClusterNodes(c.ctx, &aws.ReadClusterNodeInput{
+			ClusterID: cluster.ID,
+		})
+		if err != nil {
+			c.logger.Error(err, "failed to read cluster nodes", "ocean_id", clusterID)
+			continue
+		}
+
+		c.collectInstanceMix(ch, nodes.ClusterNode, cluster)
+	}
+}
+
+// listSpotTargets lists the clusters and returns their configured spot
+// percentage by cluster ID. Clusters without a configured spot percentage
+// are omitted.
+func (c *OceanAWSInstanceMixCollector) listSpotTargets() map[string]float64 {
+	output, err := c.clustersClient.ListClusters(c.ctx, &aws.ListClustersInput{})
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean clusters")
+		return nil
+	}
+
+	spotTargets := make(map[string]float64, len(output.Clusters))
+
+	for _, cluster := range output.Clusters {
+		if cluster.Strategy != nil && cluster.Strategy.SpotPercentage != nil {
+			spotTargets[spotinst.StringValue(cluster.ID)] = *cluster.Strategy.SpotPercentage
+		}
+	}
+
+	return spotTargets
+}
+
+func (c *OceanAWSInstanceMixCollector) collectLaunchSpecTargets(
+	ch chan<- prometheus.Metric,
+	launchSpecs []*aws.LaunchSpec,
+	cluster *aws.Cluster,
+) {
+	for _, launchSpec := range launchSpecs {
+		if launchSpec.Strategy == nil || launchSpec.Strategy.SpotPercentage == nil {
+			continue
+		}
+
+		collectGaugeValue(
+			ch,
+			c.launchSpecSpotTarget,
+			float64(*launchSpec.Strategy.SpotPercentage),
+			launchSpecLabelValues(cluster, launchSpec),
+		)
+	}
+}
+
+func (c *OceanAWSInstanceMixCollector) collectInstanceMix(
+	ch chan<- prometheus.Metric,
+	nodes []*aws.ClusterNodes,
+	cluster *aws.Cluster,
+) {
+	clusterMix := newLifecycleMix()
+	launchSpecMixes := make(map[launchSpecKey]*lifecycleMix)
+
+	for _, node := range nodes {
+		clusterMix.add(node)
+
+		key := launchSpecKey{id: spotinst.StringValue(node.LaunchSpecId), name: spotinst.StringValue(node.LaunchSpecName)}
+
+		mix, ok := launchSpecMixes[key]
+		if !ok {
+			mix = newLifecycleMix()
+			launchSpecMixes[key] = mix
+		}
+
+		mix.add(node)
+	}
+
+	labelValues := []string{spotinst.StringValue(cluster.ID), spotinst.StringValue(cluster.Name)}
+	collectLifecycleMix(ch, c.clusterInstances, c.clusterCPUShare, clusterMix, labelValues)
+
+	for key, mix := range launchSpecMixes {
+		collectLifecycleMix(ch, c.launchSpecInstances, c.launchSpecCPUShare, mix, append(labelValues, key.id, key.name))
+	}
+}
+
+// collectLifecycleMix collects the instances and CPU share per lifecycle of
+// a lifecycleMix. CPU shares are omitted if no CPU is allocatable.
+func collectLifecycleMix(
+	ch chan<- prometheus.Metric,
+	instancesDesc, cpuShareDesc *prometheus.Desc,
+	mix *lifecycleMix,
+	labelValues []string,
+) {
+	for lifecycle, instances := range mix.instances {
+		lifecycleLabelValues := append(labelValues[:len(labelValues):len(labelValues)], lifecycle)
+
+		collectGaugeValue(ch, instancesDesc, instances, lifecycleLabelValues)
+
+		if mix.totalCPU > 0 {
+			collectGaugeValue(ch, cpuShareDesc, mix.cpu[lifecycle]/mix.totalCPU, lifecycleLabelValues)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestOceanAWSInstanceMixCollector(t *testing.T) {
+	testCases := []struct {
+		name              string
+		clustersClient    func() OceanAWSClustersClient
+		nodesClient       func() OceanAWSClusterNodesClient
+		launchSpecsClient func() OceanAWSLaunchSpecsClient
+		expected          string
+		clusters          []*aws.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			clustersClient: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).
+					Return(&aws.ListClustersOutput{}, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				return new(mockOceanAWSClusterNodesClient)
+			},
+			launchSpecsClient: func() OceanAWSLaunchSpecsClient {
+				return new(mockOceanAWSLaunchSpecsClient)
+			},
+		},
+		{
+			name: "failing clients",
+			clustersClient: func() OceanAWSClustersClient {
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			launchSpecsClient: func() OceanAWSLaunchSpecsClient {
+				mockClient := new(mockOceanAWSLaunchSpecsClient)
+				mockClient.On("ListLaunchSpecs", mock.Anything, launchSpecsInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			clusters: []*aws.Cluster{
+				{
+					ID:       spotinst.String("foo"),
+					Name:     spotinst.String("ocean-foo"),
+					Strategy: &aws.Strategy{SpotPercentage: spotinst.Float64(100)},
+				},
+			},
+		},
+		{
+			name: "mixed lifecycles",
+			clustersClient: func() OceanAWSClustersClient {
+				// The spot percentage was changed after the exporter was
+				// started.
+				output := &aws.ListClustersOutput{
+					Clusters: []*aws.Cluster{
+						{
+							ID:       spotinst.String("foo"),
+							Name:     spotinst.String("ocean-foo"),
+							Strategy: &aws.Strategy{SpotPercentage: spotinst.Float64(90)},
+						},
+						{
+							ID:       spotinst.String("bar"),
+							Name:     spotinst.String("ocean-bar"),
+							Strategy: &aws.Strategy{SpotPercentage: spotinst.Float64(50)},
+						},
+					},
+				}
+
+				mockClient := new(mockOceanAWSClustersClient)
+				mockClient.On("ListClusters", mock.Anything, &aws.ListClustersInput{}).Return(output, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				output := clusterNodesOutput(
+					clusterNode("m5.large", "eu-west-1a", "SPOT", "ols-1", "default", 2000, 0, 7000, 0),
+					clusterNode("m5.large", "eu-west-1a", "SPOT", "ols-1", "default", 2000, 0, 7000, 0),
+					clusterNode("m5.xlarge", "eu-west-1a", "OD", "ols-1", "default", 4000, 0, 14000, 0),
+					clusterNode("c5.large", "eu-west-1b", "RI", "ols-2", "compute", 2000, 0, 3500, 0),
+				)
+
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			launchSpecsClient: func() OceanAWSLaunchSpecsClient {
+				output := launchSpecsOutput(
+					&aws.LaunchSpec{
+						ID:       spotinst.String("ols-1"),
+						Name:     spotinst.String("default"),
+						Strategy: &aws.LaunchSpecStrategy{SpotPercentage: spotinst.Int(80)},
+					},
+					&aws.LaunchSpec{
+						ID:   spotinst.String("ols-2"),
+						Name: spotinst.String("compute"),
+					},
+				)
+
+				mockClient := new(mockOceanAWSLaunchSpecsClient)
+				mockClient.On("ListLaunchSpecs", mock.Anything, launchSpecsInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			clusters: []*aws.Cluster{
+				{
+					ID:       spotinst.String("foo"),
+					Name:     spotinst.String("ocean-foo"),
+					Strategy: &aws.Strategy{SpotPercentage: spotinst.Float64(100)},
+				},
+			},
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_cpu_share The share of allocatable CPU units of an ocean cluster per lifecycle
+                # TYPE spotinst_ocean_aws_cluster_cpu_share gauge
+                spotinst_ocean_aws_cluster_cpu_share{lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 0.4
+                spotinst_ocean_aws_cluster_cpu_share{lifecycle="reserved",ocean_id="foo",ocean_name="ocean-foo"} 0.2
+                spotinst_ocean_aws_cluster_cpu_share{lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 0.4
+                # HELP spotinst_ocean_aws_cluster_instances The number of instances of an ocean cluster per lifecycle
+                # TYPE spotinst_ocean_aws_cluster_instances gauge
+                spotinst_ocean_aws_cluster_instances{lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_cluster_instances{lifecycle="reserved",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_cluster_instances{lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 2
+                # HELP spotinst_ocean_aws_launch_spec_cpu_share The share of allocatable CPU units of a launch spec per lifecycle
+                # TYPE spotinst_ocean_aws_launch_spec_cpu_share gauge
+                spotinst_ocean_aws_launch_spec_cpu_share{launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 0.5
+                spotinst_ocean_aws_launch_spec_cpu_share{launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 0.5
+                spotinst_ocean_aws_launch_spec_cpu_share{launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 0
+                spotinst_ocean_aws_launch_spec_cpu_share{launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="reserved",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_launch_spec_cpu_share{launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_instances The number of instances of a launch spec per lifecycle
+                # TYPE spotinst_ocean_aws_launch_spec_instances gauge
+                spotinst_ocean_aws_launch_spec_instances{launch_spec_id="ols-1",launch_spec_name="default",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_launch_spec_instances{launch_spec_id="ols-1",launch_spec_name="default",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 2
+                spotinst_ocean_aws_launch_spec_instances{launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="on-demand",ocean_id="foo",ocean_name="ocean-foo"} 0
+                spotinst_ocean_aws_launch_spec_instances{launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="reserved",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_launch_spec_instances{launch_spec_id="ols-2",launch_spec_name="compute",lifecycle="spot",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_spot_percentage_target The configured percentage of spot instances of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_spot_percentage_target gauge
+                spotinst_ocean_aws_launch_spec_spot_percentage_target{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 80
+                # HELP spotinst_ocean_aws_cluster_spot_percentage_target The configured percentage of spot instances of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_spot_percentage_target gauge
+                spotinst_ocean_aws_cluster_spot_percentage_target{ocean_id="foo",ocean_name="ocean-foo"} 90
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSInstanceMixCollector(
+				ctx,
+				logger,
+				testCase.clustersClient(),
+				testCase.nodesClient(),
+				testCase.launchSpecsClient(),
+				testCase.clusters,
+			)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package collectors
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanAWSLaunchSpecsClient is the interface for listing the launch specs
+// (Virtual Node Groups) of an Ocean cluster.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client.
+type OceanAWSLaunchSpecsClient interface {
+	ListLaunchSpecs(context.Context, *aws.ListLaunchSpecsInput) (*aws.ListLaunchSpecsOutput, error)
+}
+
+// OceanAWSLaunchSpecsCollector is a prometheus collector for the
+// configuration and node counts of the launch specs (Virtual Node Groups) of
+// Spotinst Ocean clusters on AWS.
+type OceanAWSLaunchSpecsCollector struct {
+	ctx            context.Context
+	logger         logr.Logger
+	client         OceanAWSLaunchSpecsClient
+	nodesClient    OceanAWSClusterNodesClient
+	clusters       []*aws.Cluster
+	info           *prometheus.Desc
+	minNodes       *prometheus.Desc
+	maxNodes       *prometheus.Desc
+	headroomCPU    *prometheus.Desc
+	headroomMemory *prometheus.Desc
+	headroomGPU    *prometheus.Desc
+	nodes          *prometheus.Desc
+}
+
+// NewOceanAWSLaunchSpecsCollector creates a new OceanAWSLaunchSpecsCollector
+// for collecting the launch specs of the provided list of Ocean clusters. The
+// nodesClient is used to count the nodes of each launch spec.
+func NewOceanAWSLaunchSpecsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAWSLaunchSpecsClient,
+	nodesClient OceanAWSClusterNodesClient,
+	clusters []*aws.Cluster,
+) *OceanAWSLaunchSpecsCollector {
+	labelNames := []string{"ocean_id", "ocean_name", "launch_spec_id", "launch_spec_name"}
+
+	collector := &OceanAWSLaunchSpecsCollector{
+		ctx:         ctx,
+		logger:      logger,
+		client:      client,
+		nodesClient: nodesClient,
+		clusters:    clusters,
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_info"),
+			"Information about a launch spec of an ocean cluster",
+			append(labelNames, "instance_types"),
+			nil,
+		),
+		minNodes: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_min_nodes"),
+			"The configured minimum number of nodes of a launch spec",
+			labelNames,
+			nil,
+		),
+		maxNodes: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_max_nodes"),
+			"The configured maximum number of nodes of a launch spec",
+			labelNames,
+			nil,
+		),
+		headroomCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_headroom_cpu"),
+			"The number of CPU units configured as headroom of a launch spec",
+			labelNames,
+			nil,
+		),
+		headroomMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_headroom_memory"),
+			"The number of memory units configured as headroom of a launch spec",
+			labelNames,
+			nil,
+		),
+		headroomGPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_headroom_gpu"),
+			"The number of GPUs configured as headroom of a launch spec",
+			labelNames,
+			nil,
+		),
+		nodes: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "launch_spec_nodes"),
+			"The number of nodes of a launch spec",
+			labelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSLaunchSpecsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.minNodes
+	ch <- c.maxNodes
+	ch <- c.headroomCPU
+	ch <- c.headroomMemory
+	ch <- c.headroomGPU
+	ch <- c.nodes
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSLaunchSpecsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, cluster := range c.clusters {
+		clusterID := spotinst.StringValue(cluster.ID)
+
+		output, err := c.client.ListLaunchSpecs(c.ctx, &aws.ListLaunchSpecsInput{
+			OceanID: cluster.ID,
+		})
+		if err != nil {
+			c.logger.Error(err, "failed to list launch specs", "ocean_id", clusterID)
+			continue
+		}
+
+		c.collectLaunchSpecs(ch, output.LaunchSpecs, cluster)
+
+		nodes, err := c.nodesClient.ReadClusterNodes(c.ctx, &aws.ReadClusterNodeInput{
+			ClusterID: cluster.ID,
+		})
+		if err != nil {
+			c.logger.Error(err, "failed to read cluster nodes", "ocean_id", clusterID)
+			continue
+		}
+
+		c.collectLaunchSpecNodes(ch, output.LaunchSpecs, nodes.ClusterNode, cluster)
+	}
+}
+
+func (c *OceanAWSLaunchSpecsCollector) collectLaunchSpecs(
+	ch chan<- prometheus.Metric,
+	launchSpecs []*aws.LaunchSpec,
+	cluster *aws.Cluster,
+) {
+	for _, launchSpec := range launchSpecs {
+		labelValues := launchSpecLabelValues(cluster, launchSpec)
+
+		instanceTypes := slices.Clone(launchSpec.InstanceTypes)
+		slices.Sort(instanceTypes)
+
+		collectGaugeValue(ch, c.info, 1, append(labelValues, strings.Join(instanceTypes, ",")))
+
+		if limits := launchSpec.ResourceLimits; limits != nil {
+			if limits.MinInstanceCount != nil {
+				collectGaugeValue(ch, c.minNodes, float64(*limits.MinInstanceCount), labelValues)
+			}
+
+			if limits.MaxInstanceCount != nil {
+				collectGaugeValue(ch, c.maxNodes, float64(*limits.MaxInstanceCount), labelValues)
+			}
+		}
+
+		var cpu, memory, gpu float64
+
+		if launchSpec.AutoScale != nil {
+			for _, headroom := range launchSpec.AutoScale.Headrooms {
+				units := float64(spotinst.IntValue(headroom.NumOfUnits))
+				cpu += units * float64(spotinst.IntValue(headroom.CPUPerUnit))
+				memory += units * float64(spotinst.IntValue(headroom.MemoryPerUnit))
+				gpu += units * float64(spotinst.IntValue(headroom.GPUPerUnit))
+			}
+		}
+
+		collectGaugeValue(ch, c.headroomCPU, cpu, labelValues)
+		collectGaugeValue(ch, c.headroomMemory, memory, labelValues)
+		collectGaugeValue(ch, c.headroomGPU, gpu, labelValues)
+	}
+}
+
+func (c *OceanAWSLaunchSpecsCollector) collectLaunchSpecNodes(
+	ch chan<- prometheus.Metric,
+	launchSpecs []*aws.LaunchSpec,
+	nodes []*aws.ClusterNodes,
+	cluster *aws.Cluster,
+) {
+	counts := make(map[string]float64, len(launchSpecs))
+
+	for _, node := range nodes {
+		counts[spotinst.StringValue(node.LaunchSpecId)]++
+	}
+
+	for _, launchSpec := range launchSpecs {
+		collectGaugeValue(
+			ch,
+			c.nodes,
+			counts[spotinst.StringValue(launchSpec.ID)],
+			launchSpecLabelValues(cluster, launchSpec),
+		)
+	}
+}
+
+func launchSpecLabelValues(cluster *aws.Cluster, launchSpec *aws.LaunchSpec) []string {
+	return []string{
+		spotinst.StringValue(cluster.ID),
+		spotinst.StringValue(cluster.Name),
+		spotinst.StringValue(launchSpec.ID),
+		spotinst.StringValue(launchSpec.Name),
+	}
+}
@@ -0,0 +1,188 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanAWSLaunchSpecsClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanAWSLaunchSpecsClient) ListLaunchSpecs(
+	ctx context.Context,
+	input *aws.ListLaunchSpecsInput,
+) (*aws.ListLaunchSpecsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*aws.ListLaunchSpecsOutput), args.Error(1)
+}
+
+func TestOceanAWSLaunchSpecsCollector(t *testing.T) {
+	testCases := []struct {
+		name        string
+		client      func() OceanAWSLaunchSpecsClient
+		nodesClient func() OceanAWSClusterNodesClient
+		expected    string
+		clusters    []*aws.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			client: func() OceanAWSLaunchSpecsClient {
+				return new(mockOceanAWSLaunchSpecsClient)
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				return new(mockOceanAWSClusterNodesClient)
+			},
+		},
+		{
+			name: "nonexistent cluster",
+			client: func() OceanAWSLaunchSpecsClient {
+				mockClient := new(mockOceanAWSLaunchSpecsClient)
+				mockClient.On("ListLaunchSpecs", mock.Anything, launchSpecsInput("nonexistent")).Return(nil, errors.New("nonexistent"))
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				return new(mockOceanAWSClusterNodesClient)
+			},
+			clusters: oceanClusters("nonexistent"),
+		},
+		{
+			name: "launch specs with failing nodes",
+			client: func() OceanAWSLaunchSpecsClient {
+				output := launchSpecsOutput(&aws.LaunchSpec{
+					ID:            spotinst.String("ols-1"),
+					Name:          spotinst.String("default"),
+					InstanceTypes: []string{"m5.large"},
+				})
+
+				mockClient := new(mockOceanAWSLaunchSpecsClient)
+				mockClient.On("ListLaunchSpecs", mock.Anything, launchSpecsInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_launch_spec_headroom_cpu The number of CPU units configured as headroom of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_cpu gauge
+                spotinst_ocean_aws_launch_spec_headroom_cpu{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_headroom_gpu The number of GPUs configured as headroom of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_gpu gauge
+                spotinst_ocean_aws_launch_spec_headroom_gpu{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_headroom_memory The number of memory units configured as headroom of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_memory gauge
+                spotinst_ocean_aws_launch_spec_headroom_memory{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_info Information about a launch spec of an ocean cluster
+                # TYPE spotinst_ocean_aws_launch_spec_info gauge
+                spotinst_ocean_aws_launch_spec_info{instance_types="m5.large",launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 1
+            `,
+		},
+		{
+			name: "launch specs with nodes",
+			client: func() OceanAWSLaunchSpecsClient {
+				output := launchSpecsOutput(
+					&aws.LaunchSpec{
+						ID:            spotinst.String("ols-1"),
+						Name:          spotinst.String("default"),
+						InstanceTypes: []string{"m5.large", "c5.large"},
+						ResourceLimits: &aws.ResourceLimits{
+							MinInstanceCount: spotinst.Int(1),
+							MaxInstanceCount: spotinst.Int(10),
+						},
+						AutoScale: &aws.AutoScale{
+							Headrooms: []*aws.AutoScaleHeadroom{
+								{CPUPerUnit: spotinst.Int(500), MemoryPerUnit: spotinst.Int(512), NumOfUnits: spotinst.Int(2)},
+								{CPUPerUnit: spotinst.Int(1000), GPUPerUnit: spotinst.Int(1), MemoryPerUnit: spotinst.Int(1024), NumOfUnits: spotinst.Int(1)},
+							},
+						},
+					},
+					&aws.LaunchSpec{
+						ID:   spotinst.String("ols-2"),
+						Name: spotinst.String("compute"),
+					},
+				)
+
+				mockClient := new(mockOceanAWSLaunchSpecsClient)
+				mockClient.On("ListLaunchSpecs", mock.Anything, launchSpecsInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			nodesClient: func() OceanAWSClusterNodesClient {
+				output := clusterNodesOutput(
+					clusterNode("m5.large", "eu-west-1a", "SPOT", "ols-1", "default", 1930, 900, 7000, 4000),
+					clusterNode("c5.large", "eu-west-1b", "OD", "ols-1", "default", 1930, 300, 3500, 1000),
+				)
+
+				mockClient := new(mockOceanAWSClusterNodesClient)
+				mockClient.On("ReadClusterNodes", mock.Anything, clusterNodesInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_launch_spec_headroom_cpu The number of CPU units configured as headroom of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_cpu gauge
+                spotinst_ocean_aws_launch_spec_headroom_cpu{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 2000
+                spotinst_ocean_aws_launch_spec_headroom_cpu{launch_spec_id="ols-2",launch_spec_name="compute",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_headroom_gpu The number of GPUs configured as headroom of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_gpu gauge
+                spotinst_ocean_aws_launch_spec_headroom_gpu{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_launch_spec_headroom_gpu{launch_spec_id="ols-2",launch_spec_name="compute",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_headroom_memory The number of memory units configured as headroom of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_headroom_memory gauge
+                spotinst_ocean_aws_launch_spec_headroom_memory{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 2048
+                spotinst_ocean_aws_launch_spec_headroom_memory{launch_spec_id="ols-2",launch_spec_name="compute",ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_launch_spec_info Information about a launch spec of an ocean cluster
+                # TYPE spotinst_ocean_aws_launch_spec_info gauge
+                spotinst_ocean_aws_launch_spec_info{instance_types="c5.large,m5.large",launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 1
+                spotinst_ocean_aws_launch_spec_info{instance_types="",launch_spec_id="ols-2",launch_spec_name="compute",ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_aws_launch_spec_max_nodes The configured maximum number of nodes of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_max_nodes gauge
+                spotinst_ocean_aws_launch_spec_max_nodes{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 10
+                # HELP spotinst_ocean_aws_launch_spec_min_nodes The configured minimum number of nodes of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_min_nodes gauge
+                spotinst_ocean_aws_launch_spec_min_nodes{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_aws_launch_spec_nodes The number of nodes of a launch spec
+                # TYPE spotinst_ocean_aws_launch_spec_nodes gauge
+                spotinst_ocean_aws_launch_spec_nodes{launch_spec_id="ols-1",launch_spec_name="default",ocean_id="foo",ocean_name="ocean-foo"} 2
+                spotinst_ocean_aws_launch_spec_nodes{launch_spec_id="ols-2",launch_spec_name="compute",ocean_id="foo",ocean_name="ocean-foo"} 0
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSLaunchSpecsCollector(ctx, logger, testCase.client(), testCase.nodesClient(), testCase.clusters)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func launchSpecsInput(oceanID string) *aws.ListLaunchSpecsInput {
+	return &aws.ListLaunchSpecsInput{OceanID: spotinst.String(oceanID)}
+}
+
+func launchSpecsOutput(launchSpecs ...*aws.LaunchSpec) *aws.ListLaunchSpecsOutput {
+	return &aws.ListLaunchSpecsOutput{LaunchSpecs: launchSpecs}
+}
@@ -2,8 +2,9 @@ package collectors
 
 import (
 	"context"
-	"strings"
+	"errors"
 
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
@@ -11,7 +12,8 @@ import (
 )
 
 // OceanAWSResourceSuggestionsClient is the interface for something that can
-// list Ocean resource suggestions.
+// list Ocean resource suggestions, either via the legacy resource suggestions
+// endpoint or the right-sizing recommendations API.
 //
 // It is implemented by the Spotinst *aws.ServiceOp client.
 type OceanAWSResourceSuggestionsClient interface {
@@ -19,87 +21,310 @@ type OceanAWSResourceSuggestionsClient interface {
 		context.Context,
 		*aws.ListOceanResourceSuggestionsInput,
 	) (*aws.ListOceanResourceSuggestionsOutput, error)
+	GetRightsizingRecommendations(
+		context.Context,
+		*aws.GetRightsizingRecommendationsInput,
+	) (*aws.GetRightsizingRecommendationsOutput, error)
+}
+
+var (
+	errLegacyLabelFilter = errors.New("filtering resource suggestions by label is not supported by the legacy endpoint")
+	errNegativeTolerance = errors.New("resource suggestions tolerance must not be negative")
+)
+
+// Values of the `direction` label.
+const (
+	directionOver  = "over"
+	directionUnder = "under"
+	directionOK    = "ok"
+)
+
+// ResourceSuggestionsOptions configures which resource suggestions are
+// fetched and how they are evaluated.
+type ResourceSuggestionsOptions struct {
+	// Legacy enables the legacy resource suggestions endpoint instead of the
+	// right-sizing recommendations API.
+	Legacy bool
+	// Namespaces restricts the suggestions to the listed namespaces. If
+	// empty, suggestions for all namespaces are fetched.
+	Namespaces []string
+	// LabelKey and LabelValue restrict the suggestions to workloads carrying
+	// the label. Not supported by the legacy endpoint.
+	LabelKey   string
+	LabelValue string
+	// Workloads restricts the suggestions to the listed workload kinds, e.g.
+	// "deployment". If empty, suggestions for all workload kinds are
+	// exported.
+	Workloads []string
+	// Tolerance is the absolute over-provisioning ratio up to which a
+	// resource is considered to be provisioned correctly, e.g. 0.1 for
+	// +/-10%.
+	Tolerance float64
+}
+
+// Validate returns an error if the options contain unsupported combinations.
+func (o ResourceSuggestionsOptions) Validate() error {
+	if o.Legacy && o.LabelKey != "" {
+		return errLegacyLabelFilter
+	}
+
+	if o.Tolerance < 0 {
+		return errNegativeTolerance
+	}
+
+	return nil
+}
+
+// listOceanAWSResourceSuggestions fetches the resource suggestions for an
+// Ocean cluster according to the options. Recommendations of the right-sizing
+// API are converted into resource suggestions. The suggestions are not
+// filtered by workload kind yet, as this has to happen after ReplicaSets and
+// Jobs have been rolled up into their owners, see filterSuggestionWorkloads.
+func listOceanAWSResourceSuggestions(
+	ctx context.Context,
+	client OceanAWSResourceSuggestionsClient,
+	cluster *aws.Cluster,
+	options ResourceSuggestionsOptions,
+) ([]*aws.ResourceSuggestion, error) {
+	var suggestions []*aws.ResourceSuggestion
+	var err error
+
+	if options.Legacy {
+		suggestions, err = listLegacyOceanAWSResourceSuggestions(ctx, client, cluster, options)
+	} else {
+		suggestions, err = listOceanAWSRightsizingRecommendations(ctx, client, cluster, options)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func listLegacyOceanAWSResourceSuggestions(
+	ctx context.Context,
+	client OceanAWSResourceSuggestionsClient,
+	cluster *aws.Cluster,
+	options ResourceSuggestionsOptions,
+) ([]*aws.ResourceSuggestion, error) {
+	if len(options.Namespaces) == 0 {
+		output, err := client.ListOceanResourceSuggestions(ctx, &aws.ListOceanResourceSuggestionsInput{
+			OceanID: cluster.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return output.Suggestions, nil
+	}
+
+	var suggestions []*aws.ResourceSuggestion
+
+	// The legacy endpoint only supports filtering by a single namespace.
+	for _, namespace := range options.Namespaces {
+		output, err := client.ListOceanResourceSuggestions(ctx, &aws.ListOceanResourceSuggestionsInput{
+			OceanID:   cluster.ID,
+			Namespace: spotinst.String(namespace),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, output.Suggestions...)
+	}
+
+	return suggestions, nil
+}
+
+func listOceanAWSRightsizingRecommendations(
+	ctx context.Context,
+	client OceanAWSResourceSuggestionsClient,
+	cluster *aws.Cluster,
+	options ResourceSuggestionsOptions,
+) ([]*aws.ResourceSuggestion, error) {
+	input := &aws.GetRightsizingRecommendationsInput{
+		OceanId: cluster.ID,
+	}
+
+	if len(options.Namespaces) > 0 || options.LabelKey != "" {
+		input.Filter = &aws.RecommendationFilter{}
+
+		if len(options.Namespaces) > 0 {
+			input.Filter.Namespaces = spotinst.StringSlice(options.Namespaces)
+		}
+
+		if options.LabelKey != "" {
+			input.Filter.Attribute = &aws.Attribute{
+				Type:     spotinst.String("label"),
+				Key:      spotinst.String(options.LabelKey),
+				Operator: spotinst.String("equals"),
+				Value:    spotinst.String(options.LabelValue),
+			}
+		}
+	}
+
+	output, err := client.GetRightsizingRecommendations(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*aws.ResourceSuggestion, 0, len(output.RightsizingRecommendations))
+
+	for _, recommendation := range output.RightsizingRecommendations {
+		containers := make([]*aws.ContainerResourceSuggestion, 0, len(recommendation.Containers))
+
+		for _, container := range recommendation.Containers {
+			containers = append(containers, &aws.ContainerResourceSuggestion{
+				Name:            container.Name,
+				SuggestedCPU:    container.SuggestedCPU,
+				RequestedCPU:    container.RequestedCPU,
+				SuggestedMemory: container.SuggestedMemory,
+				RequestedMemory: container.RequestedMemory,
+			})
+		}
+
+		suggestions = append(suggestions, &aws.ResourceSuggestion{
+			ResourceName:    recommendation.ResourceName,
+			ResourceType:    recommendation.ResourceType,
+			Namespace:       recommendation.Namespace,
+			SuggestedCPU:    recommendation.SuggestedCPU,
+			RequestedCPU:    recommendation.RequestedCPU,
+			SuggestedMemory: recommendation.SuggestedMemory,
+			RequestedMemory: recommendation.RequestedMemory,
+			Containers:      containers,
+		})
+	}
+
+	return suggestions, nil
+}
+
+// filterSuggestionWorkloads removes suggestions whose workload kind is not
+// contained in workloads. If workloads is empty, all suggestions are kept.
+// The owners of the suggestions must already be resolved, so that e.g. the
+// suggestions of ReplicaSets are kept if Deployments are allowed.
+func filterSuggestionWorkloads(suggestions []workloadSuggestionRecord, workloads []string) []workloadSuggestionRecord {
+	if len(workloads) == 0 {
+		return suggestions
+	}
+
+	allowed := make(map[string]bool, len(workloads))
+	for _, workload := range workloads {
+		allowed[normalizeWorkload(workload)] = true
+	}
+
+	filtered := make([]workloadSuggestionRecord, 0, len(suggestions))
+
+	for _, suggestion := range suggestions {
+		if allowed[suggestion.workload] {
+			filtered = append(filtered, suggestion)
+		}
+	}
+
+	return filtered
+}
+
+// overprovisioningRatio returns the share of the requested amount which
+// exceeds the suggested amount. The ratio is negative if the resource is
+// under-provisioned. Returns false if nothing is requested, as the ratio is
+// undefined in this case.
+func overprovisioningRatio(requested, suggested float64) (float64, bool) {
+	if requested <= 0 {
+		return 0, false
+	}
+
+	return (requested - suggested) / requested, true
+}
+
+// provisioningDirection classifies an over-provisioning ratio into one of the
+// values of the `direction` label. Ratios within +/-tolerance are considered
+// to be ok.
+func provisioningDirection(ratio, tolerance float64) string {
+	switch {
+	case ratio > tolerance:
+		return directionOver
+	case ratio < -tolerance:
+		return directionUnder
+	default:
+		return directionOK
+	}
+}
+
+// suggestionRecordsFromAWS translates the resource suggestions returned by
+// the Spotinst SDK into suggestion records.
+func suggestionRecordsFromAWS(suggestions []*aws.ResourceSuggestion) []workloadSuggestionRecord {
+	records := make([]workloadSuggestionRecord, 0, len(suggestions))
+
+	for _, suggestion := range suggestions {
+		containers := make([]containerSuggestionRecord, 0, len(suggestion.Containers))
+
+		for _, container := range suggestion.Containers {
+			containers = append(containers, containerSuggestionRecord{
+				name:            spotinst.StringValue(container.Name),
+				requestedCPU:    spotinst.Float64Value(container.RequestedCPU),
+				suggestedCPU:    spotinst.Float64Value(container.SuggestedCPU),
+				requestedMemory: spotinst.Float64Value(container.RequestedMemory),
+				suggestedMemory: spotinst.Float64Value(container.SuggestedMemory),
+			})
+		}
+
+		records = append(records, workloadSuggestionRecord{
+			workload:        normalizeWorkload(spotinst.StringValue(suggestion.ResourceType)),
+			namespace:       spotinst.StringValue(suggestion.Namespace),
+			name:            spotinst.StringValue(suggestion.ResourceName),
+			requestedCPU:    spotinst.Float64Value(suggestion.RequestedCPU),
+			suggestedCPU:    spotinst.Float64Value(suggestion.SuggestedCPU),
+			requestedMemory: spotinst.Float64Value(suggestion.RequestedMemory),
+			suggestedMemory: spotinst.Float64Value(suggestion.SuggestedMemory),
+			containers:      containers,
+		})
+	}
+
+	return records
 }
 
 // OceanAWSResourceSuggestionsCollector is a prometheus collector for the
 // resource suggestions of Spotinst Ocean clusters on AWS.
 type OceanAWSResourceSuggestionsCollector struct {
-	ctx                      context.Context
-	logger                   logr.Logger
-	client                   OceanAWSResourceSuggestionsClient
-	clusters                 []*aws.Cluster
-	requestedWorkloadCPU     *prometheus.Desc
-	suggestedWorkloadCPU     *prometheus.Desc
-	requestedWorkloadMemory  *prometheus.Desc
-	suggestedWorkloadMemory  *prometheus.Desc
-	requestedContainerCPU    *prometheus.Desc
-	suggestedContainerCPU    *prometheus.Desc
-	requestedContainerMemory *prometheus.Desc
-	suggestedContainerMemory *prometheus.Desc
+	ctx      context.Context
+	logger   logr.Logger
+	client   OceanAWSResourceSuggestionsClient
+	clusters []*aws.Cluster
+	owners   workloadOwnerResolver
+	options  ResourceSuggestionsOptions
+	emitter  suggestionEmitter
 }
 
 // NewOceanAWSResourceSuggestionsCollector creates a new
 // OceanAWSResourceSuggestionsCollector for collecting the resource suggestions
-// for the provided list of Ocean clusters.
+// for the provided list of Ocean clusters. If metadata is not nil, the labels
+// and annotations it provides are propagated onto the metrics according to the
+// labelResolver. If rollupWorkloads is true, suggestions for ReplicaSets and
+// Jobs are exported for their owning Deployments and CronJobs. The options
+// control which suggestions are fetched and the tolerance used to classify
+// workloads as over- or under-provisioned.
 func NewOceanAWSResourceSuggestionsCollector(
 	ctx context.Context,
 	logger logr.Logger,
 	client OceanAWSResourceSuggestionsClient,
 	clusters []*aws.Cluster,
+	labelResolver labels.Resolver,
+	metadata KubernetesMetadataProvider,
+	rollupWorkloads bool,
+	options ResourceSuggestionsOptions,
 ) *OceanAWSResourceSuggestionsCollector {
+	if metadata == nil {
+		metadata = noopMetadataProvider{}
+	}
+
 	collector := &OceanAWSResourceSuggestionsCollector{
 		ctx:      ctx,
 		logger:   logger,
 		client:   client,
 		clusters: clusters,
-		requestedWorkloadCPU: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_cpu_requested"),
-			"The number of actual CPU units requested by a workload",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name"},
-			nil,
-		),
-		suggestedWorkloadCPU: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_cpu_suggested"),
-			"The number of CPU units suggested for a workload",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name"},
-			nil,
-		),
-		requestedWorkloadMemory: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_memory_requested"),
-			"The number of actual memory units requested by a workload",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name"},
-			nil,
-		),
-		suggestedWorkloadMemory: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_memory_suggested"),
-			"The number of memory units suggested for a workload",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name"},
-			nil,
-		),
-		requestedContainerCPU: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_container_cpu_requested"),
-			"The number of actual CPU units requested by a workload's container",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name", "container"},
-			nil,
-		),
-		suggestedContainerCPU: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_container_cpu_suggested"),
-			"The number of CPU units suggested for a workload's container",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name", "container"},
-			nil,
-		),
-		requestedContainerMemory: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_container_memory_requested"),
-			"The number of actual memory units requested by a workload's container",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name", "container"},
-			nil,
-		),
-		suggestedContainerMemory: prometheus.NewDesc(
-			prometheus.BuildFQName("spotinst", "ocean_aws", "workload_container_memory_suggested"),
-			"The number of memory units suggested for a workload's container",
-			[]string{"ocean_id", "ocean_name", "workload", "namespace", "name", "container"},
-			nil,
-		),
+		owners:   workloadOwnerResolver{enabled: rollupWorkloads, metadata: metadata},
+		options:  options,
+		emitter:  newSuggestionEmitter(oceanSubsystem(cloudAWS), labelResolver, metadata, options.Tolerance),
 	}
 
 	return collector
@@ -107,68 +332,22 @@ func NewOceanAWSResourceSuggestionsCollector(
 
 // Describe implements the prometheus.Collector interface.
 func (c *OceanAWSResourceSuggestionsCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.requestedWorkloadCPU
-	ch <- c.suggestedWorkloadCPU
-	ch <- c.requestedWorkloadMemory
-	ch <- c.suggestedWorkloadMemory
-	ch <- c.requestedContainerCPU
-	ch <- c.suggestedContainerCPU
-	ch <- c.requestedContainerMemory
-	ch <- c.suggestedContainerMemory
+	c.emitter.describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface.
 func (c *OceanAWSResourceSuggestionsCollector) Collect(ch chan<- prometheus.Metric) {
 	for _, cluster := range c.clusters {
-		input := &aws.ListOceanResourceSuggestionsInput{
-			OceanID: cluster.ID,
-		}
-
-		output, err := c.client.ListOceanResourceSuggestions(c.ctx, input)
+		suggestions, err := listOceanAWSResourceSuggestions(c.ctx, c.client, cluster, c.options)
 		if err != nil {
 			clusterID := spotinst.StringValue(cluster.ID)
 			c.logger.Error(err, "failed to list resource suggestions", "ocean_id", clusterID)
 			continue
 		}
 
-		c.collectWorkloadSuggestions(ch, output.Suggestions, cluster)
-	}
-}
-
-func (c *OceanAWSResourceSuggestionsCollector) collectWorkloadSuggestions(
-	ch chan<- prometheus.Metric,
-	suggestions []*aws.ResourceSuggestion,
-	cluster *aws.Cluster,
-) {
-	for _, suggestion := range suggestions {
-		labelValues := []string{
-			spotinst.StringValue(cluster.ID),
-			spotinst.StringValue(cluster.Name),
-			strings.ToLower(spotinst.StringValue(suggestion.ResourceType)),
-			spotinst.StringValue(suggestion.Namespace),
-			spotinst.StringValue(suggestion.ResourceName),
-		}
-
-		collectGaugeValue(ch, c.requestedWorkloadCPU, spotinst.Float64Value(suggestion.RequestedCPU), labelValues)
-		collectGaugeValue(ch, c.suggestedWorkloadCPU, spotinst.Float64Value(suggestion.SuggestedCPU), labelValues)
-		collectGaugeValue(ch, c.requestedWorkloadMemory, spotinst.Float64Value(suggestion.RequestedMemory), labelValues)
-		collectGaugeValue(ch, c.suggestedWorkloadMemory, spotinst.Float64Value(suggestion.SuggestedMemory), labelValues)
-
-		c.collectContainerSuggestions(ch, suggestion.Containers, labelValues)
-	}
-}
-
-func (c *OceanAWSResourceSuggestionsCollector) collectContainerSuggestions(
-	ch chan<- prometheus.Metric,
-	suggestions []*aws.ContainerResourceSuggestion,
-	workloadLabelValues []string,
-) {
-	for _, suggestion := range suggestions {
-		labelValues := append(workloadLabelValues, spotinst.StringValue(suggestion.Name))
+		oceanCluster := oceanAWSCluster(cluster)
+		records := resolveSuggestionOwners(c.owners, oceanCluster.controllerClusterID, suggestionRecordsFromAWS(suggestions))
 
-		collectGaugeValue(ch, c.requestedContainerCPU, spotinst.Float64Value(suggestion.RequestedCPU), labelValues)
-		collectGaugeValue(ch, c.suggestedContainerCPU, spotinst.Float64Value(suggestion.SuggestedCPU), labelValues)
-		collectGaugeValue(ch, c.requestedContainerMemory, spotinst.Float64Value(suggestion.RequestedMemory), labelValues)
-		collectGaugeValue(ch, c.suggestedContainerMemory, spotinst.Float64Value(suggestion.SuggestedMemory), labelValues)
+		c.emitter.emit(ch, oceanCluster, filterSuggestionWorkloads(records, c.options.Workloads))
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
 	"github.com/go-logr/zapr"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
@@ -33,12 +34,30 @@ func (m *mockOceanAWSResourceSuggestionsClient) ListOceanResourceSuggestions(
 	return output.(*aws.ListOceanResourceSuggestionsOutput), args.Error(1)
 }
 
+func (m *mockOceanAWSResourceSuggestionsClient) GetRightsizingRecommendations(
+	ctx context.Context,
+	input *aws.GetRightsizingRecommendationsInput,
+) (*aws.GetRightsizingRecommendationsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*aws.GetRightsizingRecommendationsOutput), args.Error(1)
+}
+
 func TestOceanAWSResourceSuggestionsCollector(t *testing.T) {
 	testCases := []struct {
-		name     string
-		client   func() OceanAWSResourceSuggestionsClient
-		expected string
-		clusters []*aws.Cluster
+		name          string
+		client        func() OceanAWSResourceSuggestionsClient
+		expected      string
+		clusters      []*aws.Cluster
+		labelResolver labels.Resolver
+		metadata      KubernetesMetadataProvider
+		rollup        bool
+		options       ResourceSuggestionsOptions
 	}{
 		{
 			name: "no cluster, no output",
@@ -56,6 +75,7 @@ func TestOceanAWSResourceSuggestionsCollector(t *testing.T) {
 				return mockClient
 			},
 			clusters: oceanClusters("nonexistent"),
+			options:  ResourceSuggestionsOptions{Legacy: true},
 		},
 		{
 			name: "one cluster",
@@ -72,31 +92,42 @@ func TestOceanAWSResourceSuggestionsCollector(t *testing.T) {
 				return mockClient
 			},
 			clusters: oceanClusters("foo"),
+			options:  ResourceSuggestionsOptions{Legacy: true},
 			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
                 # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
-                spotinst_ocean_aws_workload_container_cpu_requested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
                 # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
-                spotinst_ocean_aws_workload_container_cpu_suggested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
                 # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
-                spotinst_ocean_aws_workload_container_memory_requested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
                 # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
-                spotinst_ocean_aws_workload_container_memory_suggested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
                 # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
                 # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
-                spotinst_ocean_aws_workload_cpu_requested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
                 # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
                 # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
-                spotinst_ocean_aws_workload_cpu_suggested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
                 # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
                 # TYPE spotinst_ocean_aws_workload_memory_requested gauge
-                spotinst_ocean_aws_workload_memory_requested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
                 # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
                 # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
-                spotinst_ocean_aws_workload_memory_suggested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
             `,
 		},
 		{
@@ -122,39 +153,54 @@ func TestOceanAWSResourceSuggestionsCollector(t *testing.T) {
 				return mockClient
 			},
 			clusters: oceanClusters("foo"),
+			options:  ResourceSuggestionsOptions{Legacy: true},
 			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 0.8008008008008008
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 0.9504752376188094
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
                 # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
-                spotinst_ocean_aws_workload_container_cpu_requested{container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 899
-                spotinst_ocean_aws_workload_container_cpu_requested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 899
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
                 # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
-                spotinst_ocean_aws_workload_container_cpu_suggested{container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 199
-                spotinst_ocean_aws_workload_container_cpu_suggested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 199
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
                 # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
-                spotinst_ocean_aws_workload_container_memory_requested{container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 1799
-                spotinst_ocean_aws_workload_container_memory_requested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 1799
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
                 # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
                 # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
-                spotinst_ocean_aws_workload_container_memory_suggested{container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 89
-                spotinst_ocean_aws_workload_container_memory_suggested{container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 89
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
                 # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
                 # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
-                spotinst_ocean_aws_workload_cpu_requested{name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 999
-                spotinst_ocean_aws_workload_cpu_requested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 999
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
                 # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
                 # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
-                spotinst_ocean_aws_workload_cpu_suggested{name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 199
-                spotinst_ocean_aws_workload_cpu_suggested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 199
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
                 # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
                 # TYPE spotinst_ocean_aws_workload_memory_requested gauge
-                spotinst_ocean_aws_workload_memory_requested{name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 1999
-                spotinst_ocean_aws_workload_memory_requested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 1999
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
                 # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
                 # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
-                spotinst_ocean_aws_workload_memory_suggested{name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 99
-                spotinst_ocean_aws_workload_memory_suggested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 99
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
             `,
 		},
 		{
@@ -183,23 +229,524 @@ func TestOceanAWSResourceSuggestionsCollector(t *testing.T) {
 				return mockClient
 			},
 			clusters: oceanClusters("foo", "nonexistent", "bar"),
+			options:  ResourceSuggestionsOptions{Legacy: true},
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",resource="memory"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 0.8008008008008008
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 0.9504752376188094
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 999
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 199
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 1999
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 99
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+            `,
+		},
+		{
+			name: "enrich with kubernetes metadata",
+			client: func() OceanAWSResourceSuggestionsClient {
+				input := resourceSuggestionsInput("foo")
+				output := resourceSuggestionsOutput(resourceSuggestion(
+					"foo-deployment", "Deployment", "foo-ns",
+					200, 1000, 100, 2000,
+					containerResourceSuggestion("foo-container", 200, 900, 90, 1800),
+				))
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			options:  ResourceSuggestionsOptions{Legacy: true},
+			metadata: fakeMetadataProvider{
+				metadata: map[string]map[string]string{
+					"foo/foo-ns":                           {"cost-center": "1234"},
+					"foo/foo-ns/deployment/foo-deployment": {"owner": "alice"},
+				},
+			},
+			labelResolver: func() labels.Resolver {
+				mappings, _ := labels.ParseMappings("owner,cost-center=cost_center")
+				return labels.NewResolver(mappings, labels.Sources{labels.SourceNamespace}, false)
+			}(),
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",cost_center="1234",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",cost_center="1234",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 0.95
+                # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 900
+                # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 1800
+                # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 90
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 1000
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 2000
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",cost_center="1234",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",owner="alice",workload="deployment"} 100
+            `,
+		},
+		{
+			name: "normalize and roll up workloads",
+			client: func() OceanAWSResourceSuggestionsClient {
+				input := resourceSuggestionsInput("foo")
+				output := resourceSuggestionsOutput(
+					resourceSuggestion(
+						"foo-deployment-5d8f9c7b6", "ReplicaSet", "foo-ns",
+						200, 1000, 100, 2000,
+						containerResourceSuggestion("foo-container", 200, 900, 90, 1800),
+					),
+					resourceSuggestion(
+						"foo-deployment-7c9d8f6b5", "ReplicaSet", "foo-ns",
+						300, 1000, 200, 2000,
+						containerResourceSuggestion("foo-container", 300, 900, 190, 1800),
+					),
+					resourceSuggestion(
+						"bar-daemonset", "Daemon_Set", "foo-ns",
+						100, 500, 50, 900,
+						containerResourceSuggestion("bar-container", 100, 400, 50, 800),
+					),
+				)
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			options:  ResourceSuggestionsOptions{Legacy: true},
+			rollup:   true,
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 2
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 2
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 0.8
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 0.9444444444444444
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
+                # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 400
+                # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 100
+                # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 800
+                # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="bar-container",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 50
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 500
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 100
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 900
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="bar-daemonset",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="daemonset"} 50
+            `,
+		},
+		{
+			name: "filter rolled up workloads",
+			client: func() OceanAWSResourceSuggestionsClient {
+				input := resourceSuggestionsInput("foo")
+				output := resourceSuggestionsOutput(
+					resourceSuggestion(
+						"foo-deployment-5d8f9c7b6", "ReplicaSet", "foo-ns",
+						200, 1000, 100, 2000,
+						containerResourceSuggestion("foo-container", 200, 900, 90, 1800),
+					),
+					resourceSuggestion(
+						"foo-deployment-7c9d8f6b5", "ReplicaSet", "foo-ns",
+						300, 1000, 200, 2000,
+						containerResourceSuggestion("foo-container", 300, 900, 190, 1800),
+					),
+					resourceSuggestion(
+						"bar-daemonset", "Daemon_Set", "foo-ns",
+						100, 500, 50, 900,
+						containerResourceSuggestion("bar-container", 100, 400, 50, 800),
+					),
+				)
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			options:  ResourceSuggestionsOptions{Legacy: true, Workloads: []string{"deployment"}},
+			rollup:   true,
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
+                # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+            `,
+		},
+		{
+			name: "rightsizing recommendations with filters",
+			client: func() OceanAWSResourceSuggestionsClient {
+				input := &aws.GetRightsizingRecommendationsInput{
+					OceanId: spotinst.String("foo"),
+					Filter: &aws.RecommendationFilter{
+						Namespaces: spotinst.StringSlice([]string{"foo-ns"}),
+						Attribute: &aws.Attribute{
+							Type:     spotinst.String("label"),
+							Key:      spotinst.String("team"),
+							Operator: spotinst.String("equals"),
+							Value:    spotinst.String("foo-team"),
+						},
+					},
+				}
+				output := &aws.GetRightsizingRecommendationsOutput{
+					RightsizingRecommendations: []*aws.RightsizingRecommendation{
+						{
+							ResourceName:    spotinst.String("foo-deployment"),
+							ResourceType:    spotinst.String("Deployment"),
+							Namespace:       spotinst.String("foo-ns"),
+							SuggestedCPU:    spotinst.Float64(200),
+							RequestedCPU:    spotinst.Float64(1000),
+							SuggestedMemory: spotinst.Float64(100),
+							RequestedMemory: spotinst.Float64(2000),
+							Containers: []*aws.RightsizingRecommendationContainer{
+								{
+									Name:            spotinst.String("foo-container"),
+									SuggestedCPU:    spotinst.Float64(200),
+									RequestedCPU:    spotinst.Float64(900),
+									SuggestedMemory: spotinst.Float64(90),
+									RequestedMemory: spotinst.Float64(1800),
+								},
+							},
+						},
+						{
+							ResourceName: spotinst.String("foo-daemonset"),
+							ResourceType: spotinst.String("DaemonSet"),
+							Namespace:    spotinst.String("foo-ns"),
+						},
+					},
+				}
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("GetRightsizingRecommendations", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			options: ResourceSuggestionsOptions{
+				Namespaces: []string{"foo-ns"},
+				LabelKey:   "team",
+				LabelValue: "foo-team",
+				Workloads:  []string{"deployment", "statefulset"},
+			},
 			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
+                # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+            `,
+		},
+		{
+			name: "legacy endpoint with namespaces",
+			client: func() OceanAWSResourceSuggestionsClient {
+				fooInput := &aws.ListOceanResourceSuggestionsInput{
+					OceanID:   spotinst.String("foo"),
+					Namespace: spotinst.String("foo-ns"),
+				}
+				fooOutput := resourceSuggestionsOutput(resourceSuggestion(
+					"foo-deployment", "deployment", "foo-ns",
+					200, 1000, 100, 2000,
+				))
+				barInput := &aws.ListOceanResourceSuggestionsInput{
+					OceanID:   spotinst.String("foo"),
+					Namespace: spotinst.String("bar-ns"),
+				}
+				barOutput := resourceSuggestionsOutput(resourceSuggestion(
+					"bar-deployment", "deployment", "bar-ns",
+					100, 500, 50, 900,
+				))
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, fooInput).Return(fooOutput, nil)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, barInput).Return(barOutput, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			options: ResourceSuggestionsOptions{
+				Legacy:     true,
+				Namespaces: []string{"foo-ns", "bar-ns"},
+			},
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.8
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.9444444444444444
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
                 # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
                 # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
-                spotinst_ocean_aws_workload_cpu_requested{name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 999
-                spotinst_ocean_aws_workload_cpu_requested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 500
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
                 # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
                 # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
-                spotinst_ocean_aws_workload_cpu_suggested{name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 199
-                spotinst_ocean_aws_workload_cpu_suggested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
                 # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
                 # TYPE spotinst_ocean_aws_workload_memory_requested gauge
-                spotinst_ocean_aws_workload_memory_requested{name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 1999
-                spotinst_ocean_aws_workload_memory_requested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 2000
                 # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
                 # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
-                spotinst_ocean_aws_workload_memory_suggested{name="bar-daemonset",namespace="bar-ns",ocean_id="bar",ocean_name="ocean-bar",workload="daemonset"} 99
-                spotinst_ocean_aws_workload_memory_suggested{name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 50
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+            `,
+		},
+		{
+			name: "overprovisioning direction with tolerance",
+			client: func() OceanAWSResourceSuggestionsClient {
+				input := resourceSuggestionsInput("foo")
+				output := resourceSuggestionsOutput(
+					resourceSuggestion("foo-deployment", "deployment", "foo-ns", 500, 1000, 105, 100),
+					resourceSuggestion("bar-deployment", "deployment", "foo-ns", 300, 200, 50, 0),
+				)
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			options:  ResourceSuggestionsOptions{Legacy: true, Tolerance: 0.1},
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 0
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.5
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="under",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} -0.5
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="ok",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} -0.05
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1000
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 300
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 500
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 50
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 105
+            `,
+		},
+		{
+			name: "container limits from kubernetes metadata",
+			client: func() OceanAWSResourceSuggestionsClient {
+				input := resourceSuggestionsInput("foo")
+				output := resourceSuggestionsOutput(resourceSuggestion(
+					"foo-deployment", "deployment", "foo-ns",
+					250, 900, 90, 1800,
+					containerResourceSuggestion("foo-container", 200, 900, 90, 1800),
+					containerResourceSuggestion("sidecar", 50, 0, 0, 0),
+					containerResourceSuggestion("unlimited", 0, 0, 0, 0),
+				))
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			metadata: fakeMetadataProvider{
+				limits: map[string][2]float64{
+					"foo/foo-ns/deployment/foo-deployment/foo-container": {1800, 3600},
+					"foo/foo-ns/deployment/foo-deployment/sidecar":       {100, 0},
+				},
+			},
+			options: ResourceSuggestionsOptions{Legacy: true},
+			expected: `
+                # HELP spotinst_ocean_aws_namespace_overprovisioned_workloads The number of workloads in a namespace which are over-provisioned for a resource
+                # TYPE spotinst_ocean_aws_namespace_overprovisioned_workloads gauge
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="cpu"} 1
+                spotinst_ocean_aws_namespace_overprovisioned_workloads{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",resource="memory"} 1
+                # HELP spotinst_ocean_aws_workload_container_cpu_limit The number of CPU units a workload's container is limited to
+                # TYPE spotinst_ocean_aws_workload_container_cpu_limit gauge
+                spotinst_ocean_aws_workload_container_cpu_limit{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                spotinst_ocean_aws_workload_container_cpu_limit{cloud="aws",container="sidecar",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 100
+                # HELP spotinst_ocean_aws_workload_container_cpu_limit_suggested The number of CPU units suggested as limit for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_limit_suggested gauge
+                spotinst_ocean_aws_workload_container_cpu_limit_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 400
+                # HELP spotinst_ocean_aws_workload_container_cpu_requested The number of actual CPU units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_requested gauge
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="sidecar",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                spotinst_ocean_aws_workload_container_cpu_requested{cloud="aws",container="unlimited",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                # HELP spotinst_ocean_aws_workload_container_cpu_suggested The number of CPU units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_cpu_suggested gauge
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 200
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="sidecar",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 50
+                spotinst_ocean_aws_workload_container_cpu_suggested{cloud="aws",container="unlimited",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                # HELP spotinst_ocean_aws_workload_container_memory_limit The number of memory units a workload's container is limited to
+                # TYPE spotinst_ocean_aws_workload_container_memory_limit gauge
+                spotinst_ocean_aws_workload_container_memory_limit{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 3600
+                # HELP spotinst_ocean_aws_workload_container_memory_limit_suggested The number of memory units suggested as limit for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_limit_suggested gauge
+                spotinst_ocean_aws_workload_container_memory_limit_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 180
+                # HELP spotinst_ocean_aws_workload_container_memory_requested The number of actual memory units requested by a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_requested gauge
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="sidecar",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                spotinst_ocean_aws_workload_container_memory_requested{cloud="aws",container="unlimited",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                # HELP spotinst_ocean_aws_workload_container_memory_suggested The number of memory units suggested for a workload's container
+                # TYPE spotinst_ocean_aws_workload_container_memory_suggested gauge
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="foo-container",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="sidecar",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                spotinst_ocean_aws_workload_container_memory_suggested{cloud="aws",container="unlimited",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0
+                # HELP spotinst_ocean_aws_workload_cpu_overprovisioning_ratio The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_cpu_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_cpu_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.7222222222222222
+                # HELP spotinst_ocean_aws_workload_cpu_requested The number of actual CPU units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_requested gauge
+                spotinst_ocean_aws_workload_cpu_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 900
+                # HELP spotinst_ocean_aws_workload_cpu_suggested The number of CPU units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_cpu_suggested gauge
+                spotinst_ocean_aws_workload_cpu_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 250
+                # HELP spotinst_ocean_aws_workload_memory_overprovisioning_ratio The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned
+                # TYPE spotinst_ocean_aws_workload_memory_overprovisioning_ratio gauge
+                spotinst_ocean_aws_workload_memory_overprovisioning_ratio{cloud="aws",direction="over",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 0.95
+                # HELP spotinst_ocean_aws_workload_memory_requested The number of actual memory units requested by a workload
+                # TYPE spotinst_ocean_aws_workload_memory_requested gauge
+                spotinst_ocean_aws_workload_memory_requested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 1800
+                # HELP spotinst_ocean_aws_workload_memory_suggested The number of memory units suggested for a workload
+                # TYPE spotinst_ocean_aws_workload_memory_suggested gauge
+                spotinst_ocean_aws_workload_memory_suggested{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 90
             `,
 		},
 	}
@@ -209,7 +756,7 @@ func TestOceanAWSResourceSuggestionsCollector(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			ctx := context.Background()
-			collector := NewOceanAWSResourceSuggestionsCollector(ctx, logger, testCase.client(), testCase.clusters)
+			collector := NewOceanAWSResourceSuggestionsCollector(ctx, logger, testCase.client(), testCase.clusters, testCase.labelResolver, testCase.metadata, testCase.rollup, testCase.options)
 
 			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
 		})
@@ -255,3 +802,11 @@ func containerResourceSuggestion(
 		RequestedMemory: spotinst.Float64(rmem),
 	}
 }
+
+func TestResourceSuggestionsOptions(t *testing.T) {
+	assert.NoError(t, ResourceSuggestionsOptions{}.Validate())
+	assert.NoError(t, ResourceSuggestionsOptions{LabelKey: "team"}.Validate())
+	assert.NoError(t, ResourceSuggestionsOptions{Legacy: true, Namespaces: []string{"foo-ns"}}.Validate())
+	assert.Error(t, ResourceSuggestionsOptions{Legacy: true, LabelKey: "team"}.Validate())
+	assert.Error(t, ResourceSuggestionsOptions{Tolerance: -0.1}.Validate())
+}
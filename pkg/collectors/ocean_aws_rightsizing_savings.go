@@ -0,0 +1,199 @@
+package collectors
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/mcs"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// cpuCostShare is the share of a workload's cost which is attributed to its
+// CPU requests. The remainder is attributed to its memory requests.
+const cpuCostShare = 0.5
+
+// OceanAWSRightsizingSavingsCollector is a prometheus collector for the
+// potential savings of applying the resource suggestions of Spotinst Ocean
+// clusters on AWS.
+//
+// The savings are estimated with a proportional model: the cost of a
+// workload in the current month is split between CPU and memory according to
+// cpuCostShare. For each resource, the share of the requested amount which
+// exceeds the suggested amount is considered to be saved. Resources which are
+// under-provisioned do not contribute negative savings.
+//
+// The collector fetches the same costs and suggestions as the costs and
+// resource suggestions collectors. Pass a *CachingClient to all of them to
+// share the requests.
+type OceanAWSRightsizingSavingsCollector struct {
+	ctx               context.Context
+	logger            logr.Logger
+	costsClient       OceanClusterCostsClient
+	suggestionsClient OceanAWSResourceSuggestionsClient
+	clusters          []*aws.Cluster
+	owners            workloadOwnerResolver
+	options           ResourceSuggestionsOptions
+	emitter           costEmitter
+}
+
+// NewOceanAWSRightsizingSavingsCollector creates a new
+// OceanAWSRightsizingSavingsCollector for estimating the potential savings of
+// the provided list of Ocean clusters. The remaining arguments have the same
+// meaning as for the costs and resource suggestions collectors.
+func NewOceanAWSRightsizingSavingsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	costsClient OceanClusterCostsClient,
+	suggestionsClient OceanAWSResourceSuggestionsClient,
+	clusters []*aws.Cluster,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+	rollupWorkloads bool,
+	options ResourceSuggestionsOptions,
+) *OceanAWSRightsizingSavingsCollector {
+	if metadata == nil {
+		metadata = noopMetadataProvider{}
+	}
+
+	collector := &OceanAWSRightsizingSavingsCollector{
+		ctx:               ctx,
+		logger:            logger,
+		costsClient:       costsClient,
+		suggestionsClient: suggestionsClient,
+		clusters:          clusters,
+		owners:            workloadOwnerResolver{enabled: rollupWorkloads, metadata: metadata},
+		options:           options,
+		emitter:           newSavingsEmitter(oceanSubsystem(cloudAWS), labelResolver, clusterTagMappings, metadata),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSRightsizingSavingsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.emitter.describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSRightsizingSavingsCollector) Collect(ch chan<- prometheus.Metric) {
+	fromDate, toDate := currentMonth(time.Now())
+
+	for _, cluster := range c.clusters {
+		oceanCluster := oceanAWSCluster(cluster)
+
+		costs, err := c.costsClient.GetClusterCosts(c.ctx, &mcs.ClusterCostInput{
+			ClusterID: spotinst.String(oceanCluster.controllerClusterID),
+			FromDate:  fromDate,
+			ToDate:    toDate,
+		})
+		if err != nil {
+			c.logger.Error(err, "failed to fetch cluster costs", "ocean_id", oceanCluster.id)
+			continue
+		}
+
+		suggestions, err := listOceanAWSResourceSuggestions(c.ctx, c.suggestionsClient, cluster, c.options)
+		if err != nil {
+			c.logger.Error(err, "failed to list resource suggestions", "ocean_id", oceanCluster.id)
+			continue
+		}
+
+		costRecords := costRecordsFromMCS(c.owners, oceanCluster.controllerClusterID, costs.ClusterCosts)
+		suggestionRecords := filterSuggestionWorkloads(
+			resolveSuggestionOwners(c.owners, oceanCluster.controllerClusterID, suggestionRecordsFromAWS(suggestions)),
+			c.options.Workloads,
+		)
+
+		c.emitter.emit(ch, oceanCluster, []clusterCostRecord{savingsRecord(costRecords, suggestionRecords)})
+	}
+}
+
+// savingsRecord estimates the potential savings of applying the suggestions
+// to the workloads of the cost records. The costs of the returned record are
+// the savings. Only namespaces and workloads with suggestions are included.
+func savingsRecord(costs []clusterCostRecord, suggestions []workloadSuggestionRecord) clusterCostRecord {
+	suggestionsByKey := aggregateHighCardinalitySuggestions(suggestions)
+
+	var record clusterCostRecord
+
+	for _, cost := range costs {
+		for _, namespace := range cost.namespaces {
+			namespaceRecord := namespaceCostRecord{namespace: namespace.namespace, labels: namespace.labels}
+
+			for _, workload := range namespace.workloads {
+				key := workloadKey{namespace: namespace.namespace, workload: workload.workload, name: workload.name}
+
+				suggestion, ok := suggestionsByKey[key]
+				if !ok {
+					continue
+				}
+
+				savings := potentialSavings(workload.cost, suggestion)
+				namespaceRecord.cost += savings
+				namespaceRecord.workloads = append(namespaceRecord.workloads, workloadCostRecord{
+					workload: workload.workload,
+					name:     workload.name,
+					cost:     savings,
+					labels:   workload.labels,
+				})
+			}
+
+			if len(namespaceRecord.workloads) == 0 {
+				continue
+			}
+
+			record.cost += namespaceRecord.cost
+			record.namespaces = append(record.namespaces, namespaceRecord)
+		}
+	}
+
+	return record
+}
+
+// aggregateHighCardinalitySuggestions removes timestamps and UUIDs from the
+// names of the suggestions the same way as from the names of cost records, so
+// that both can be joined. The requested and suggested resources of
+// suggestions whose names are equal afterwards are summed up, like their
+// costs are.
+func aggregateHighCardinalitySuggestions(suggestions []workloadSuggestionRecord) map[workloadKey]workloadSuggestionRecord {
+	aggregated := make(map[workloadKey]workloadSuggestionRecord, len(suggestions))
+
+	for _, suggestion := range suggestions {
+		suggestion.name = normalizeResourceName(suggestion.name)
+
+		if existing, ok := aggregated[suggestion.key()]; ok {
+			suggestion.requestedCPU += existing.requestedCPU
+			suggestion.suggestedCPU += existing.suggestedCPU
+			suggestion.requestedMemory += existing.requestedMemory
+			suggestion.suggestedMemory += existing.suggestedMemory
+		}
+
+		aggregated[suggestion.key()] = suggestion
+	}
+
+	return aggregated
+}
+
+// potentialSavings estimates the share of cost which could be saved by
+// applying a resource suggestion.
+func potentialSavings(cost float64, suggestion workloadSuggestionRecord) float64 {
+	cpuShare := overprovisionedShare(suggestion.requestedCPU, suggestion.suggestedCPU)
+	memoryShare := overprovisionedShare(suggestion.requestedMemory, suggestion.suggestedMemory)
+
+	return cost * (cpuCostShare*cpuShare + (1-cpuCostShare)*memoryShare)
+}
+
+// overprovisionedShare returns the share of the requested amount which
+// exceeds the suggested amount, or zero if the resource is not
+// over-provisioned.
+func overprovisionedShare(requested, suggested float64) float64 {
+	if requested <= 0 || suggested >= requested {
+		return 0
+	}
+
+	return (requested - suggested) / requested
+}
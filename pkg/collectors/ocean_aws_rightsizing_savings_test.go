@@ -0,0 +1,139 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/mcs"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestOceanAWSRightsizingSavingsCollector(t *testing.T) {
+	testCases := []struct {
+		name              string
+		costsClient       func() OceanClusterCostsClient
+		suggestionsClient func() OceanAWSResourceSuggestionsClient
+		expected          string
+		clusters          []*aws.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			costsClient: func() OceanClusterCostsClient {
+				return new(mockOceanClusterCostsClient)
+			},
+			suggestionsClient: func() OceanAWSResourceSuggestionsClient {
+				return new(mockOceanAWSResourceSuggestionsClient)
+			},
+		},
+		{
+			name: "failing suggestions",
+			costsClient: func() OceanClusterCostsClient {
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, clusterCostInput("foo")).Return(clusterCostOutput(200), nil)
+				return mockClient
+			},
+			suggestionsClient: func() OceanAWSResourceSuggestionsClient {
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, resourceSuggestionsInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+		},
+		{
+			name: "one cluster",
+			costsClient: func() OceanClusterCostsClient {
+				output := clusterCostOutput(
+					200,
+					&mcs.Namespace{
+						Namespace: spotinst.String("foo-ns"),
+						Cost:      spotinst.Float64(190),
+						Deployments: []*mcs.Resource{
+							resourceCost("foo-ns", "foo-deployment", 100),
+							resourceCost("foo-ns", "bar-deployment", 50),
+							resourceCost("foo-ns", "baz-deployment", 40),
+						},
+						Jobs: []*mcs.Resource{
+							resourceCost("foo-ns", "foo-job-27745697", 30),
+							resourceCost("foo-ns", "foo-job-27745937", 10),
+						},
+					},
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, clusterCostInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			suggestionsClient: func() OceanAWSResourceSuggestionsClient {
+				output := resourceSuggestionsOutput(
+					// 50% CPU and 75% memory over-provisioned.
+					resourceSuggestion("foo-deployment", "Deployment", "foo-ns", 500, 1000, 500, 2000),
+					// CPU under-provisioned, 50% memory over-provisioned.
+					resourceSuggestion("bar-deployment", "Deployment", "foo-ns", 2000, 1000, 1000, 2000),
+					// No cost data.
+					resourceSuggestion("qux-deployment", "Deployment", "foo-ns", 500, 1000, 500, 2000),
+					// Aggregated to 50% CPU over-provisioned, like the costs
+					// of the Jobs.
+					resourceSuggestion("foo-job-27745697", "Job", "foo-ns", 500, 1000, 1000, 1000),
+					resourceSuggestion("foo-job-27745937", "Job", "foo-ns", 500, 1000, 1000, 1000),
+				)
+
+				mockClient := new(mockOceanAWSResourceSuggestionsClient)
+				mockClient.On("ListOceanResourceSuggestions", mock.Anything, resourceSuggestionsInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClustersTags(map[string]string{"team": "foo-team"}, "foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_rightsizing_potential_savings Estimated cost that could have been saved in an ocean cluster by applying the resource suggestions
+                # TYPE spotinst_ocean_aws_cluster_rightsizing_potential_savings gauge
+                spotinst_ocean_aws_cluster_rightsizing_potential_savings{cloud="aws",ocean_id="foo",ocean_name="ocean-foo",team="foo-team"} 85
+                # HELP spotinst_ocean_aws_namespace_rightsizing_potential_savings Estimated cost that could have been saved in a namespace by applying the resource suggestions
+                # TYPE spotinst_ocean_aws_namespace_rightsizing_potential_savings gauge
+                spotinst_ocean_aws_namespace_rightsizing_potential_savings{cloud="aws",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 85
+                # HELP spotinst_ocean_aws_workload_rightsizing_potential_savings Estimated cost that could have been saved for a workload by applying the resource suggestions
+                # TYPE spotinst_ocean_aws_workload_rightsizing_potential_savings gauge
+                spotinst_ocean_aws_workload_rightsizing_potential_savings{cloud="aws",name="bar-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 12.5
+                spotinst_ocean_aws_workload_rightsizing_potential_savings{cloud="aws",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 62.5
+                spotinst_ocean_aws_workload_rightsizing_potential_savings{cloud="aws",name="foo-job",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="job"} 10
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+	clusterTagMappings, _ := labels.ParseMappings("team")
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSRightsizingSavingsCollector(
+				ctx,
+				logger,
+				testCase.costsClient(),
+				testCase.suggestionsClient(),
+				testCase.clusters,
+				labels.Resolver{},
+				clusterTagMappings,
+				nil,
+				false,
+				ResourceSuggestionsOptions{Legacy: true},
+			)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func TestPotentialSavings(t *testing.T) {
+	assert.Equal(t, 0.0, potentialSavings(100, workloadSuggestionRecord{suggestedCPU: 0, requestedCPU: 0, suggestedMemory: 0, requestedMemory: 0}))
+	assert.Equal(t, 0.0, potentialSavings(100, workloadSuggestionRecord{suggestedCPU: 200, requestedCPU: 100, suggestedMemory: 200, requestedMemory: 100}))
+	assert.Equal(t, 100.0, potentialSavings(100, workloadSuggestionRecord{suggestedCPU: 0, requestedCPU: 100, suggestedMemory: 0, requestedMemory: 100}))
+	assert.Equal(t, 25.0, potentialSavings(100, workloadSuggestionRecord{suggestedCPU: 50, requestedCPU: 100, suggestedMemory: 100, requestedMemory: 100}))
+}
@@ -0,0 +1,250 @@
+package collectors
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// Normalized values of the `status` label of rolls.
+const (
+	rollStatusInProgress = "in_progress"
+	rollStatusFailed     = "failed"
+)
+
+// rollRetention is the time after which finished rolls are no longer exported
+// as info metrics, in order to keep the cardinality bounded. Failed rolls are
+// only counted if they finished within the retention.
+const rollRetention = 24 * time.Hour
+
+// rollsState holds the failed rolls counter of a cluster, the failed rolls
+// which were already counted and whether a roll was in progress when the
+// rolls were last listed.
+type rollsState struct {
+	failed     float64
+	inProgress float64
+	// seenFailed holds the IDs of the counted failed rolls with the time
+	// they were last updated.
+	seenFailed map[string]time.Time
+}
+
+// update counts the failed roll if it was not counted before and finished
+// after cutoff.
+func (s *rollsState) update(roll *aws.RollStatus, cutoff time.Time) {
+	id := spotinst.StringValue(roll.ID)
+	updatedAt := rollUpdatedAt(roll)
+
+	if _, ok := s.seenFailed[id]; ok || updatedAt.Before(cutoff) {
+		return
+	}
+
+	s.seenFailed[id] = updatedAt
+	s.failed++
+}
+
+// prune removes the failed rolls which finished before cutoff, as they are
+// not counted anymore anyway.
+func (s *rollsState) prune(cutoff time.Time) {
+	for id, updatedAt := range s.seenFailed {
+		if updatedAt.Before(cutoff) {
+			delete(s.seenFailed, id)
+		}
+	}
+}
+
+// OceanAWSRollsClient is the interface for listing the rolls of an Ocean
+// cluster.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client.
+type OceanAWSRollsClient interface {
+	ListRolls(context.Context, *aws.ListRollsInput) (*aws.ListRollsOutput, error)
+}
+
+// OceanAWSRollsCollector is a prometheus collector for the rolls of Spotinst
+// Ocean clusters on AWS.
+//
+// The failed rolls counters are kept in memory and each failed roll is only
+// counted once.
+type OceanAWSRollsCollector struct {
+	ctx            context.Context
+	logger         logr.Logger
+	client         OceanAWSRollsClient
+	clusters       []*aws.Cluster
+	now            func() time.Time
+	mu             sync.Mutex
+	states         map[string]*rollsState
+	inProgress     *prometheus.Desc
+	progress       *prometheus.Desc
+	info           *prometheus.Desc
+	startTimestamp *prometheus.Desc
+	failed         *prometheus.Desc
+}
+
+// NewOceanAWSRollsCollector creates a new OceanAWSRollsCollector for
+// collecting the rolls of the provided list of Ocean clusters.
+func NewOceanAWSRollsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAWSRollsClient,
+	clusters []*aws.Cluster,
+) *OceanAWSRollsCollector {
+	collector := &OceanAWSRollsCollector{
+		ctx:      ctx,
+		logger:   logger,
+		client:   client,
+		clusters: clusters,
+		now:      time.Now,
+		states:   make(map[string]*rollsState, len(clusters)),
+		inProgress: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_roll_in_progress"),
+			"Whether a roll of an ocean cluster is in progress",
+			[]string{"ocean_id", "ocean_name"},
+			nil,
+		),
+		progress: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_roll_progress_percent"),
+			"The progress of a roll of an ocean cluster which is in progress",
+			[]string{"ocean_id", "ocean_name", "roll_id"},
+			nil,
+		),
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_roll_info"),
+			"Information about a recent roll of an ocean cluster",
+			[]string{"ocean_id", "ocean_name", "roll_id", "status"},
+			nil,
+		),
+		startTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_roll_start_timestamp_seconds"),
+			"The time a recent roll of an ocean cluster was started",
+			[]string{"ocean_id", "ocean_name", "roll_id"},
+			nil,
+		),
+		failed: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_aws", "cluster_rolls_failed_total"),
+			"The number of failed rolls of an ocean cluster",
+			[]string{"ocean_id", "ocean_name"},
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAWSRollsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.inProgress
+	ch <- c.progress
+	ch <- c.info
+	ch <- c.startTimestamp
+	ch <- c.failed
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAWSRollsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cluster := range c.clusters {
+		clusterID := spotinst.StringValue(cluster.ID)
+
+		state, ok := c.states[clusterID]
+		if !ok {
+			state = &rollsState{seenFailed: make(map[string]time.Time)}
+			c.states[clusterID] = state
+		}
+
+		output, err := c.client.ListRolls(c.ctx, &aws.ListRollsInput{
+			ClusterID: cluster.ID,
+		})
+		if err != nil {
+			// The counter and the last known roll state are still exported
+			// in order to avoid gaps.
+			c.logger.Error(err, "failed to list rolls", "ocean_id", clusterID)
+		} else {
+			c.collectRolls(ch, output.Rolls, state, cluster)
+		}
+
+		labelValues := []string{clusterID, spotinst.StringValue(cluster.Name)}
+
+		collectGaugeValue(ch, c.inProgress, state.inProgress, labelValues)
+		collectCounterValue(ch, c.failed, state.failed, labelValues)
+	}
+}
+
+func (c *OceanAWSRollsCollector) collectRolls(
+	ch chan<- prometheus.Metric,
+	rolls []*aws.RollStatus,
+	state *rollsState,
+	cluster *aws.Cluster,
+) {
+	labelValues := []string{spotinst.StringValue(cluster.ID), spotinst.StringValue(cluster.Name)}
+	cutoff := c.now().Add(-rollRetention)
+
+	state.inProgress = 0
+
+	for _, roll := range rolls {
+		status := normalizeRollStatus(spotinst.StringValue(roll.Status))
+		rollLabelValues := append(labelValues, spotinst.StringValue(roll.ID))
+
+		switch status {
+		case rollStatusInProgress:
+			state.inProgress = 1
+
+			if progress, ok := rollProgress(roll); ok {
+				collectGaugeValue(ch, c.progress, progress, rollLabelValues)
+			}
+		case rollStatusFailed:
+			state.update(roll, cutoff)
+		}
+
+		if status != rollStatusInProgress && rollUpdatedAt(roll).Before(cutoff) {
+			continue
+		}
+
+		collectGaugeValue(ch, c.info, 1, append(rollLabelValues, status))
+
+		if roll.CreatedAt != nil {
+			collectGaugeValue(ch, c.startTimestamp, float64(roll.CreatedAt.Unix()), rollLabelValues)
+		}
+	}
+
+	state.prune(cutoff)
+}
+
+// normalizeRollStatus converts a roll status as returned by the Spotinst API,
+// e.g. "IN_PROGRESS", into the normalized value of the `status` label.
+func normalizeRollStatus(status string) string {
+	return strings.ReplaceAll(strings.ToLower(status), "-", "_")
+}
+
+// rollProgress returns the progress of a roll in percent. If the progress is
+// not reported in percent, it is derived from the batches. Returns false if
+// the progress is unknown.
+func rollProgress(roll *aws.RollStatus) (float64, bool) {
+	if progress := roll.Progress; progress != nil && progress.Value != nil &&
+		strings.EqualFold(spotinst.StringValue(progress.Unit), "percent") {
+		return *progress.Value, true
+	}
+
+	if batches := spotinst.IntValue(roll.NumOfBatches); batches > 0 {
+		return float64(spotinst.IntValue(roll.CurrentBatch)) / float64(batches) * 100, true
+	}
+
+	return 0, false
+}
+
+// rollUpdatedAt returns the time a roll was last updated, falling back to the
+// time it was created.
+func rollUpdatedAt(roll *aws.RollStatus) time.Time {
+	if roll.UpdatedAt != nil {
+		return *roll.UpdatedAt
+	}
+
+	return spotinst.TimeValue(roll.CreatedAt)
+}
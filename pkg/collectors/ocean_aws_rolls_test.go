@@ -0,0 +1,264 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanAWSRollsClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanAWSRollsClient) ListRolls(
+	ctx context.Context,
+	input *aws.ListRollsInput,
+) (*aws.ListRollsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*aws.ListRollsOutput), args.Error(1)
+}
+
+func TestOceanAWSRollsCollector(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		client   func() OceanAWSRollsClient
+		expected string
+		clusters []*aws.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			client: func() OceanAWSRollsClient {
+				return new(mockOceanAWSRollsClient)
+			},
+		},
+		{
+			name: "nonexistent cluster",
+			client: func() OceanAWSRollsClient {
+				mockClient := new(mockOceanAWSRollsClient)
+				mockClient.On("ListRolls", mock.Anything, rollsInput("nonexistent")).Return(nil, errors.New("nonexistent"))
+				return mockClient
+			},
+			clusters: oceanClusters("nonexistent"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_roll_in_progress Whether a roll of an ocean cluster is in progress
+                # TYPE spotinst_ocean_aws_cluster_roll_in_progress gauge
+                spotinst_ocean_aws_cluster_roll_in_progress{ocean_id="nonexistent",ocean_name="ocean-nonexistent"} 0
+                # HELP spotinst_ocean_aws_cluster_rolls_failed_total The number of failed rolls of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_rolls_failed_total counter
+                spotinst_ocean_aws_cluster_rolls_failed_total{ocean_id="nonexistent",ocean_name="ocean-nonexistent"} 0
+            `,
+		},
+		{
+			name: "no rolls",
+			client: func() OceanAWSRollsClient {
+				mockClient := new(mockOceanAWSRollsClient)
+				mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).Return(&aws.ListRollsOutput{}, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_roll_in_progress Whether a roll of an ocean cluster is in progress
+                # TYPE spotinst_ocean_aws_cluster_roll_in_progress gauge
+                spotinst_ocean_aws_cluster_roll_in_progress{ocean_id="foo",ocean_name="ocean-foo"} 0
+                # HELP spotinst_ocean_aws_cluster_rolls_failed_total The number of failed rolls of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_rolls_failed_total counter
+                spotinst_ocean_aws_cluster_rolls_failed_total{ocean_id="foo",ocean_name="ocean-foo"} 0
+            `,
+		},
+		{
+			name: "rolls",
+			client: func() OceanAWSRollsClient {
+				output := &aws.ListRollsOutput{Rolls: []*aws.RollStatus{
+					{
+						ID:        spotinst.String("scr-1"),
+						Status:    spotinst.String("IN_PROGRESS"),
+						Progress:  &aws.Progress{Unit: spotinst.String("percent"), Value: spotinst.Float64(40)},
+						CreatedAt: spotinst.Time(now.Add(-time.Hour)),
+					},
+					{
+						ID:           spotinst.String("scr-2"),
+						Status:       spotinst.String("IN_PROGRESS"),
+						CurrentBatch: spotinst.Int(1),
+						NumOfBatches: spotinst.Int(4),
+						CreatedAt:    spotinst.Time(now.Add(-time.Hour)),
+					},
+					{
+						ID:        spotinst.String("scr-3"),
+						Status:    spotinst.String("FAILED"),
+						CreatedAt: spotinst.Time(now.Add(-3 * time.Hour)),
+						UpdatedAt: spotinst.Time(now.Add(-2 * time.Hour)),
+					},
+					{
+						// Finished too long ago to be exported or counted.
+						ID:        spotinst.String("scr-4"),
+						Status:    spotinst.String("FAILED"),
+						CreatedAt: spotinst.Time(now.Add(-72 * time.Hour)),
+						UpdatedAt: spotinst.Time(now.Add(-48 * time.Hour)),
+					},
+				}}
+
+				mockClient := new(mockOceanAWSRollsClient)
+				mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanClusters("foo"),
+			expected: `
+                # HELP spotinst_ocean_aws_cluster_roll_in_progress Whether a roll of an ocean cluster is in progress
+                # TYPE spotinst_ocean_aws_cluster_roll_in_progress gauge
+                spotinst_ocean_aws_cluster_roll_in_progress{ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_aws_cluster_roll_info Information about a recent roll of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_roll_info gauge
+                spotinst_ocean_aws_cluster_roll_info{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-1",status="in_progress"} 1
+                spotinst_ocean_aws_cluster_roll_info{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-2",status="in_progress"} 1
+                spotinst_ocean_aws_cluster_roll_info{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-3",status="failed"} 1
+                # HELP spotinst_ocean_aws_cluster_roll_progress_percent The progress of a roll of an ocean cluster which is in progress
+                # TYPE spotinst_ocean_aws_cluster_roll_progress_percent gauge
+                spotinst_ocean_aws_cluster_roll_progress_percent{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-1"} 40
+                spotinst_ocean_aws_cluster_roll_progress_percent{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-2"} 25
+                # HELP spotinst_ocean_aws_cluster_roll_start_timestamp_seconds The time a recent roll of an ocean cluster was started
+                # TYPE spotinst_ocean_aws_cluster_roll_start_timestamp_seconds gauge
+                spotinst_ocean_aws_cluster_roll_start_timestamp_seconds{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-1"} 1.7673516e+09
+                spotinst_ocean_aws_cluster_roll_start_timestamp_seconds{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-2"} 1.7673516e+09
+                spotinst_ocean_aws_cluster_roll_start_timestamp_seconds{ocean_id="foo",ocean_name="ocean-foo",roll_id="scr-3"} 1.7673444e+09
+                # HELP spotinst_ocean_aws_cluster_rolls_failed_total The number of failed rolls of an ocean cluster
+                # TYPE spotinst_ocean_aws_cluster_rolls_failed_total counter
+                spotinst_ocean_aws_cluster_rolls_failed_total{ocean_id="foo",ocean_name="ocean-foo"} 1
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAWSRollsCollector(ctx, logger, testCase.client(), testCase.clusters)
+			collector.now = func() time.Time { return now }
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func TestOceanAWSRollsCollectorFailedRolls(t *testing.T) {
+	start := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	failedRoll := func(id string, updatedAt time.Time) *aws.RollStatus {
+		return &aws.RollStatus{
+			ID:        spotinst.String(id),
+			Status:    spotinst.String("FAILED"),
+			CreatedAt: spotinst.Time(updatedAt.Add(-time.Hour)),
+			UpdatedAt: spotinst.Time(updatedAt),
+		}
+	}
+
+	mockClient := new(mockOceanAWSRollsClient)
+	mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).
+		Return(&aws.ListRollsOutput{Rolls: []*aws.RollStatus{
+			failedRoll("scr-1", start.Add(-time.Hour)),
+		}}, nil).Once()
+	mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).
+		Return(&aws.ListRollsOutput{Rolls: []*aws.RollStatus{
+			failedRoll("scr-1", start.Add(-time.Hour)),
+			failedRoll("scr-2", start.Add(time.Minute)),
+		}}, nil).Once()
+	mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).Return(nil, errors.New("error")).Once()
+	mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).
+		Return(&aws.ListRollsOutput{Rolls: []*aws.RollStatus{
+			failedRoll("scr-2", start.Add(time.Minute)),
+		}}, nil)
+
+	logger := zapr.NewLogger(zap.NewNop())
+	collector := NewOceanAWSRollsCollector(context.Background(), logger, mockClient, oceanClusters("foo"))
+
+	now := start
+	collector.now = func() time.Time { return now }
+
+	expectFailed := func(failed string) {
+		t.Helper()
+
+		expected := `
+            # HELP spotinst_ocean_aws_cluster_rolls_failed_total The number of failed rolls of an ocean cluster
+            # TYPE spotinst_ocean_aws_cluster_rolls_failed_total counter
+            spotinst_ocean_aws_cluster_rolls_failed_total{ocean_id="foo",ocean_name="ocean-foo"} ` + failed + `
+        `
+
+		assert.NoError(t, testutil.CollectAndCompare(
+			collector,
+			strings.NewReader(expected),
+			"spotinst_ocean_aws_cluster_rolls_failed_total",
+		))
+	}
+
+	expectFailed("1")
+
+	// Failed rolls are only counted once.
+	now = start.Add(2 * time.Minute)
+	expectFailed("2")
+
+	// Errors do not leave a gap in the counter.
+	now = start.Add(4 * time.Minute)
+	expectFailed("2")
+
+	// Rolls which are no longer returned do not decrease the counter.
+	now = start.Add(6 * time.Minute)
+	expectFailed("2")
+}
+
+func TestOceanAWSRollsCollectorListError(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	mockClient := new(mockOceanAWSRollsClient)
+	mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).
+		Return(&aws.ListRollsOutput{Rolls: []*aws.RollStatus{
+			{
+				ID:        spotinst.String("scr-1"),
+				Status:    spotinst.String("IN_PROGRESS"),
+				CreatedAt: spotinst.Time(now.Add(-time.Hour)),
+			},
+		}}, nil).Once()
+	mockClient.On("ListRolls", mock.Anything, rollsInput("foo")).Return(nil, errors.New("error"))
+
+	logger := zapr.NewLogger(zap.NewNop())
+	collector := NewOceanAWSRollsCollector(context.Background(), logger, mockClient, oceanClusters("foo"))
+	collector.now = func() time.Time { return now }
+
+	expected := `
+        # HELP spotinst_ocean_aws_cluster_roll_in_progress Whether a roll of an ocean cluster is in progress
+        # TYPE spotinst_ocean_aws_cluster_roll_in_progress gauge
+        spotinst_ocean_aws_cluster_roll_in_progress{ocean_id="foo",ocean_name="ocean-foo"} 1
+    `
+
+	// The last known state is kept if the rolls cannot be listed.
+	for range 2 {
+		assert.NoError(t, testutil.CollectAndCompare(
+			collector,
+			strings.NewReader(expected),
+			"spotinst_ocean_aws_cluster_roll_in_progress",
+		))
+	}
+
+	mockClient.AssertExpectations(t)
+}
+
+func rollsInput(oceanID string) *aws.ListRollsInput {
+	return &aws.ListRollsInput{ClusterID: spotinst.String(oceanID)}
+}
@@ -0,0 +1,74 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanAzureClusterCostsCollector is a prometheus collector for the cost of
+// Spotinst Ocean clusters on Azure.
+type OceanAzureClusterCostsCollector struct {
+	oceanClusterCostsCollector
+}
+
+// NewOceanAzureClusterCostsCollector creates a new
+// OceanAzureClusterCostsCollector for collecting the costs of the provided
+// list of Ocean clusters. The clusterTagMappings are used to propagate the
+// tags of the virtual node group template of the Ocean clusters onto the
+// cluster-level metrics. The remaining arguments behave like the ones of
+// NewOceanAWSClusterCostsCollector.
+func NewOceanAzureClusterCostsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanClusterCostsClient,
+	clusters []*azure.Cluster,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+	rollupWorkloads bool,
+) *OceanAzureClusterCostsCollector {
+	oceanClusters := make([]oceanCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		oceanClusters = append(oceanClusters, oceanAzureCluster(cluster))
+	}
+
+	return &OceanAzureClusterCostsCollector{
+		oceanClusterCostsCollector: newOceanClusterCostsCollector(
+			ctx,
+			logger,
+			cloudAzure,
+			client,
+			oceanClusters,
+			labelResolver,
+			clusterTagMappings,
+			metadata,
+			rollupWorkloads,
+		),
+	}
+}
+
+// oceanAzureCluster translates an Ocean cluster on Azure into its cloud provider
+// independent representation.
+func oceanAzureCluster(cluster *azure.Cluster) oceanCluster {
+	return oceanCluster{
+		cloud:               cloudAzure,
+		id:                  spotinst.StringValue(cluster.ID),
+		name:                spotinst.StringValue(cluster.Name),
+		controllerClusterID: spotinst.StringValue(cluster.ControllerClusterID),
+		tags:                oceanAzureClusterTags(cluster),
+	}
+}
+
+// oceanAzureClusterTags returns the tags configured on the virtual node group
+// template of an Ocean cluster.
+func oceanAzureClusterTags(cluster *azure.Cluster) map[string]string {
+	if cluster.VirtualNodeGroupTemplate == nil || cluster.VirtualNodeGroupTemplate.Tags == nil {
+		return nil
+	}
+
+	return *cluster.VirtualNodeGroupTemplate.Tags
+}
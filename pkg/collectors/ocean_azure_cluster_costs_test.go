@@ -0,0 +1,85 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestOceanAzureClusterCostsCollector(t *testing.T) {
+	testCases := []struct {
+		name               string
+		client             func() OceanClusterCostsClient
+		expected           string
+		clusterTagMappings labels.Mappings
+		clusters           []*azure.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			client: func() OceanClusterCostsClient {
+				return new(mockOceanClusterCostsClient)
+			},
+		},
+		{
+			name: "nonexistent cluster",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("nonexistent")
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(nil, errors.New("nonexistent"))
+				return mockClient
+			},
+			clusters: oceanAzureClusters(nil, "nonexistent"),
+		},
+		{
+			name: "cluster tags",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(
+					200,
+					namespaceCost("foo-ns", 190, resourceCost("foo-ns", "foo-deployment", 180)),
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanAzureClusters(map[string]string{"team": "platform"}, "foo"),
+			clusterTagMappings: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_ocean_azure_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_azure_cluster_cost gauge
+                spotinst_ocean_azure_cluster_cost{cloud="azure",ocean_id="foo",ocean_name="ocean-foo",team="platform"} 200
+                # HELP spotinst_ocean_azure_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_azure_namespace_cost gauge
+                spotinst_ocean_azure_namespace_cost{cloud="azure",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 190
+                # HELP spotinst_ocean_azure_workload_cost Total cost of a workload
+                # TYPE spotinst_ocean_azure_workload_cost gauge
+                spotinst_ocean_azure_workload_cost{cloud="azure",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 180
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAzureClusterCostsCollector(ctx, logger, testCase.client(), testCase.clusters, labels.Resolver{}, testCase.clusterTagMappings, nil, false)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanAzureClustersClient is the interface for listing Ocean clusters on
+// Azure.
+//
+// It is implemented by the Spotinst *azure_np.ServiceOp client.
+type OceanAzureClustersClient interface {
+	ListClusters(context.Context) (*azure.ListClustersOutput, error)
+}
+
+// OceanAzureClusterInfoCollector is a prometheus collector for the
+// configuration of Spotinst Ocean clusters on Azure.
+//
+// Like the OceanAWSClusterInfoCollector, it lists the clusters on every
+// collection in order to reflect configuration changes made after the
+// exporter was started, and propagates the cluster tags mapped by
+// clusterTagMappings onto the cluster info metric.
+type OceanAzureClusterInfoCollector struct {
+	ctx                context.Context
+	logger             logr.Logger
+	client             OceanAzureClustersClient
+	clusterTagMappings labels.Mappings
+	info               *prometheus.Desc
+	minCapacity        *prometheus.Desc
+	maxCapacity        *prometheus.Desc
+}
+
+// NewOceanAzureClusterInfoCollector creates a new
+// OceanAzureClusterInfoCollector for collecting the configuration of all
+// Ocean clusters on Azure of the account.
+func NewOceanAzureClusterInfoCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAzureClustersClient,
+	clusterTagMappings labels.Mappings,
+) *OceanAzureClusterInfoCollector {
+	labelNames := []string{"ocean_id", "ocean_name"}
+
+	collector := &OceanAzureClusterInfoCollector{
+		ctx:                ctx,
+		logger:             logger,
+		client:             client,
+		clusterTagMappings: clusterTagMappings,
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "cluster_info"),
+			"Information about the configuration of an ocean cluster",
+			append(
+				append(
+					labelNames,
+					"region",
+					"resource_group",
+					"aks_cluster_name",
+					"controller_cluster_id",
+					"spot_percentage",
+					"fallback_to_on_demand",
+				),
+				clusterTagMappings.LabelNames()...,
+			),
+			nil,
+		),
+		minCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "cluster_capacity_min"),
+			"The configured minimum number of nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+		maxCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "cluster_capacity_max"),
+			"The configured maximum number of nodes of an ocean cluster",
+			labelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAzureClusterInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.minCapacity
+	ch <- c.maxCapacity
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAzureClusterInfoCollector) Collect(ch chan<- prometheus.Metric) {
+	output, err := c.client.ListClusters(c.ctx)
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean azure clusters")
+		return
+	}
+
+	for _, cluster := range output.Clusters {
+		c.collectClusterInfo(ch, cluster)
+	}
+}
+
+func (c *OceanAzureClusterInfoCollector) collectClusterInfo(ch chan<- prometheus.Metric, cluster *azure.Cluster) {
+	labelValues := []string{spotinst.StringValue(cluster.ID), spotinst.StringValue(cluster.Name)}
+
+	var region, resourceGroup, aksClusterName string
+
+	if aks := cluster.AKS; aks != nil {
+		region = spotinst.StringValue(aks.Region)
+		resourceGroup = spotinst.StringValue(aks.ResourceGroupName)
+		aksClusterName = spotinst.StringValue(aks.ClusterName)
+	}
+
+	var spotPercentage, fallbackToOnDemand string
+
+	template := cluster.VirtualNodeGroupTemplate
+	if template != nil && template.Strategy != nil {
+		spotPercentage = formatOptionalInt(template.Strategy.SpotPercentage)
+		fallbackToOnDemand = formatOptionalBool(template.Strategy.FallbackToOD)
+	}
+
+	infoLabelValues := append(
+		labelValues,
+		region,
+		resourceGroup,
+		aksClusterName,
+		spotinst.StringValue(cluster.ControllerClusterID),
+		spotPercentage,
+		fallbackToOnDemand,
+	)
+
+	collectGaugeValue(ch, c.info, 1, append(infoLabelValues, c.clusterTagMappings.LabelValues(oceanAzureClusterTags(cluster))...))
+
+	if template != nil && template.NodeCountLimits != nil {
+		collectOptionalGaugeValue(ch, c.minCapacity, template.NodeCountLimits.MinCount, 1, labelValues)
+		collectOptionalGaugeValue(ch, c.maxCapacity, template.NodeCountLimits.MaxCount, 1, labelValues)
+	}
+}
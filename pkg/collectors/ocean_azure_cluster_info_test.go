@@ -0,0 +1,171 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanAzureClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanAzureClient) ListClusters(ctx context.Context) (*azure.ListClustersOutput, error) {
+	args := m.Called(ctx)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*azure.ListClustersOutput), args.Error(1)
+}
+
+func (m *mockOceanAzureClient) ListVirtualNodeGroups(
+	ctx context.Context,
+	input *azure.ListVirtualNodeGroupsInput,
+) (*azure.ListVirtualNodeGroupsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*azure.ListVirtualNodeGroupsOutput), args.Error(1)
+}
+
+func TestOceanAzureClusterInfoCollector(t *testing.T) {
+	testCases := []struct {
+		name               string
+		client             func() OceanAzureClustersClient
+		clusterTagMappings labels.Mappings
+		expected           string
+	}{
+		{
+			name: "failing cluster list",
+			client: func() OceanAzureClustersClient {
+				mockClient := new(mockOceanAzureClient)
+				mockClient.On("ListClusters", mock.Anything).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "cluster without configuration",
+			client: func() OceanAzureClustersClient {
+				output := &azure.ListClustersOutput{Clusters: oceanAzureClusters(nil, "foo")}
+
+				mockClient := new(mockOceanAzureClient)
+				mockClient.On("ListClusters", mock.Anything).Return(output, nil)
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_azure_cluster_info Information about the configuration of an ocean cluster
+                # TYPE spotinst_ocean_azure_cluster_info gauge
+                spotinst_ocean_azure_cluster_info{aks_cluster_name="",controller_cluster_id="foo",fallback_to_on_demand="",ocean_id="foo",ocean_name="ocean-foo",region="",resource_group="",spot_percentage=""} 1
+            `,
+		},
+		{
+			name: "cluster with configuration",
+			client: func() OceanAzureClustersClient {
+				output := &azure.ListClustersOutput{Clusters: []*azure.Cluster{
+					{
+						ID:                  spotinst.String("foo"),
+						ControllerClusterID: spotinst.String("foo-controller"),
+						Name:                spotinst.String("ocean-foo"),
+						AKS: &azure.AKS{
+							ClusterName:       spotinst.String("aks-foo"),
+							ResourceGroupName: spotinst.String("rg-foo"),
+							Region:            spotinst.String("westeurope"),
+						},
+						VirtualNodeGroupTemplate: &azure.VirtualNodeGroupTemplate{
+							NodeCountLimits: &azure.NodeCountLimits{
+								MinCount: spotinst.Int(1),
+								MaxCount: spotinst.Int(50),
+							},
+							Strategy: &azure.Strategy{
+								SpotPercentage: spotinst.Int(80),
+								FallbackToOD:   spotinst.Bool(true),
+							},
+						},
+					},
+				}}
+
+				mockClient := new(mockOceanAzureClient)
+				mockClient.On("ListClusters", mock.Anything).Return(output, nil)
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_azure_cluster_capacity_max The configured maximum number of nodes of an ocean cluster
+                # TYPE spotinst_ocean_azure_cluster_capacity_max gauge
+                spotinst_ocean_azure_cluster_capacity_max{ocean_id="foo",ocean_name="ocean-foo"} 50
+                # HELP spotinst_ocean_azure_cluster_capacity_min The configured minimum number of nodes of an ocean cluster
+                # TYPE spotinst_ocean_azure_cluster_capacity_min gauge
+                spotinst_ocean_azure_cluster_capacity_min{ocean_id="foo",ocean_name="ocean-foo"} 1
+                # HELP spotinst_ocean_azure_cluster_info Information about the configuration of an ocean cluster
+                # TYPE spotinst_ocean_azure_cluster_info gauge
+                spotinst_ocean_azure_cluster_info{aks_cluster_name="aks-foo",controller_cluster_id="foo-controller",fallback_to_on_demand="true",ocean_id="foo",ocean_name="ocean-foo",region="westeurope",resource_group="rg-foo",spot_percentage="80"} 1
+            `,
+		},
+		{
+			name: "cluster tags",
+			client: func() OceanAzureClustersClient {
+				output := &azure.ListClustersOutput{Clusters: oceanAzureClusters(map[string]string{"team": "foo-team"}, "foo")}
+
+				mockClient := new(mockOceanAzureClient)
+				mockClient.On("ListClusters", mock.Anything).Return(output, nil)
+				return mockClient
+			},
+			clusterTagMappings: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("team,cost-center=cost_center")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_ocean_azure_cluster_info Information about the configuration of an ocean cluster
+                # TYPE spotinst_ocean_azure_cluster_info gauge
+                spotinst_ocean_azure_cluster_info{aks_cluster_name="",controller_cluster_id="foo",cost_center="",fallback_to_on_demand="",ocean_id="foo",ocean_name="ocean-foo",region="",resource_group="",spot_percentage="",team="foo-team"} 1
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAzureClusterInfoCollector(ctx, logger, testCase.client(), testCase.clusterTagMappings)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func oceanAzureClusters(tags map[string]string, clusterIDs ...string) []*azure.Cluster {
+	clusters := make([]*azure.Cluster, 0, len(clusterIDs))
+
+	for _, id := range clusterIDs {
+		cluster := &azure.Cluster{
+			ID:                  spotinst.String(id),
+			ControllerClusterID: spotinst.String(id),
+			Name:                spotinst.String("ocean-" + id),
+		}
+
+		if tags != nil {
+			cluster.VirtualNodeGroupTemplate = &azure.VirtualNodeGroupTemplate{Tags: &tags}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
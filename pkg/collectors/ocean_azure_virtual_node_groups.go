@@ -0,0 +1,134 @@
+package collectors
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanAzureVirtualNodeGroupsClient is the interface for listing the virtual
+// node groups of an Ocean cluster on Azure.
+//
+// It is implemented by the Spotinst *azure_np.ServiceOp client.
+type OceanAzureVirtualNodeGroupsClient interface {
+	ListVirtualNodeGroups(
+		context.Context,
+		*azure.ListVirtualNodeGroupsInput,
+	) (*azure.ListVirtualNodeGroupsOutput, error)
+}
+
+// OceanAzureVirtualNodeGroupsCollector is a prometheus collector for the
+// virtual node groups of Spotinst Ocean clusters on Azure.
+type OceanAzureVirtualNodeGroupsCollector struct {
+	ctx                  context.Context
+	logger               logr.Logger
+	client               OceanAzureVirtualNodeGroupsClient
+	clusters             []*azure.Cluster
+	info                 *prometheus.Desc
+	minNodes             *prometheus.Desc
+	maxNodes             *prometheus.Desc
+	spotPercentageTarget *prometheus.Desc
+}
+
+// NewOceanAzureVirtualNodeGroupsCollector creates a new
+// OceanAzureVirtualNodeGroupsCollector for collecting the virtual node groups
+// of the provided list of Ocean clusters.
+func NewOceanAzureVirtualNodeGroupsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanAzureVirtualNodeGroupsClient,
+	clusters []*azure.Cluster,
+) *OceanAzureVirtualNodeGroupsCollector {
+	labelNames := []string{"ocean_id", "ocean_name", "virtual_node_group_id", "virtual_node_group_name"}
+
+	collector := &OceanAzureVirtualNodeGroupsCollector{
+		ctx:      ctx,
+		logger:   logger,
+		client:   client,
+		clusters: clusters,
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "virtual_node_group_info"),
+			"Information about the configuration of a virtual node group",
+			append(labelNames, "availability_zones"),
+			nil,
+		),
+		minNodes: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "virtual_node_group_min_nodes"),
+			"The configured minimum number of nodes of a virtual node group",
+			labelNames,
+			nil,
+		),
+		maxNodes: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "virtual_node_group_max_nodes"),
+			"The configured maximum number of nodes of a virtual node group",
+			labelNames,
+			nil,
+		),
+		spotPercentageTarget: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_azure", "virtual_node_group_spot_percentage_target"),
+			"The configured percentage of spot instances of a virtual node group",
+			labelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanAzureVirtualNodeGroupsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.minNodes
+	ch <- c.maxNodes
+	ch <- c.spotPercentageTarget
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanAzureVirtualNodeGroupsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, cluster := range c.clusters {
+		output, err := c.client.ListVirtualNodeGroups(c.ctx, &azure.ListVirtualNodeGroupsInput{
+			OceanID: cluster.ID,
+		})
+		if err != nil {
+			clusterID := spotinst.StringValue(cluster.ID)
+			c.logger.Error(err, "failed to list virtual node groups", "ocean_id", clusterID)
+			continue
+		}
+
+		c.collectVirtualNodeGroups(ch, output.VirtualNodeGroups, cluster)
+	}
+}
+
+func (c *OceanAzureVirtualNodeGroupsCollector) collectVirtualNodeGroups(
+	ch chan<- prometheus.Metric,
+	virtualNodeGroups []*azure.VirtualNodeGroup,
+	cluster *azure.Cluster,
+) {
+	for _, virtualNodeGroup := range virtualNodeGroups {
+		labelValues := []string{
+			spotinst.StringValue(cluster.ID),
+			spotinst.StringValue(cluster.Name),
+			spotinst.StringValue(virtualNodeGroup.ID),
+			spotinst.StringValue(virtualNodeGroup.Name),
+		}
+
+		zones := slices.Clone(virtualNodeGroup.AvailabilityZones)
+		slices.Sort(zones)
+
+		collectGaugeValue(ch, c.info, 1, append(labelValues, strings.Join(zones, ",")))
+
+		if limits := virtualNodeGroup.NodeCountLimits; limits != nil {
+			collectOptionalGaugeValue(ch, c.minNodes, limits.MinCount, 1, labelValues)
+			collectOptionalGaugeValue(ch, c.maxNodes, limits.MaxCount, 1, labelValues)
+		}
+
+		if strategy := virtualNodeGroup.Strategy; strategy != nil {
+			collectOptionalGaugeValue(ch, c.spotPercentageTarget, strategy.SpotPercentage, 1, labelValues)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	azure "github.com/spotinst/spotinst-sdk-go/service/ocean/providers/azure_np"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestOceanAzureVirtualNodeGroupsCollector(t *testing.T) {
+	testCases := []struct {
+		name     string
+		client   func() OceanAzureVirtualNodeGroupsClient
+		expected string
+		clusters []*azure.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			client: func() OceanAzureVirtualNodeGroupsClient {
+				return new(mockOceanAzureClient)
+			},
+		},
+		{
+			name: "failing client",
+			client: func() OceanAzureVirtualNodeGroupsClient {
+				mockClient := new(mockOceanAzureClient)
+				mockClient.On("ListVirtualNodeGroups", mock.Anything, virtualNodeGroupsInput("foo")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			clusters: oceanAzureClusters(nil, "foo"),
+		},
+		{
+			name: "virtual node groups",
+			client: func() OceanAzureVirtualNodeGroupsClient {
+				output := &azure.ListVirtualNodeGroupsOutput{VirtualNodeGroups: []*azure.VirtualNodeGroup{
+					{
+						ID:                spotinst.String("vng-1"),
+						Name:              spotinst.String("default"),
+						AvailabilityZones: []string{"2", "1", "3"},
+						NodeCountLimits: &azure.NodeCountLimits{
+							MinCount: spotinst.Int(0),
+							MaxCount: spotinst.Int(100),
+						},
+						Strategy: &azure.Strategy{SpotPercentage: spotinst.Int(100)},
+					},
+					{
+						ID:   spotinst.String("vng-2"),
+						Name: spotinst.String("system"),
+					},
+				}}
+
+				mockClient := new(mockOceanAzureClient)
+				mockClient.On("ListVirtualNodeGroups", mock.Anything, virtualNodeGroupsInput("foo")).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanAzureClusters(nil, "foo"),
+			expected: `
+                # HELP spotinst_ocean_azure_virtual_node_group_info Information about the configuration of a virtual node group
+                # TYPE spotinst_ocean_azure_virtual_node_group_info gauge
+                spotinst_ocean_azure_virtual_node_group_info{availability_zones="1,2,3",ocean_id="foo",ocean_name="ocean-foo",virtual_node_group_id="vng-1",virtual_node_group_name="default"} 1
+                spotinst_ocean_azure_virtual_node_group_info{availability_zones="",ocean_id="foo",ocean_name="ocean-foo",virtual_node_group_id="vng-2",virtual_node_group_name="system"} 1
+                # HELP spotinst_ocean_azure_virtual_node_group_max_nodes The configured maximum number of nodes of a virtual node group
+                # TYPE spotinst_ocean_azure_virtual_node_group_max_nodes gauge
+                spotinst_ocean_azure_virtual_node_group_max_nodes{ocean_id="foo",ocean_name="ocean-foo",virtual_node_group_id="vng-1",virtual_node_group_name="default"} 100
+                # HELP spotinst_ocean_azure_virtual_node_group_min_nodes The configured minimum number of nodes of a virtual node group
+                # TYPE spotinst_ocean_azure_virtual_node_group_min_nodes gauge
+                spotinst_ocean_azure_virtual_node_group_min_nodes{ocean_id="foo",ocean_name="ocean-foo",virtual_node_group_id="vng-1",virtual_node_group_name="default"} 0
+                # HELP spotinst_ocean_azure_virtual_node_group_spot_percentage_target The configured percentage of spot instances of a virtual node group
+                # TYPE spotinst_ocean_azure_virtual_node_group_spot_percentage_target gauge
+                spotinst_ocean_azure_virtual_node_group_spot_percentage_target{ocean_id="foo",ocean_name="ocean-foo",virtual_node_group_id="vng-1",virtual_node_group_name="default"} 100
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanAzureVirtualNodeGroupsCollector(ctx, logger, testCase.client(), testCase.clusters)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func virtualNodeGroupsInput(oceanID string) *azure.ListVirtualNodeGroupsInput {
+	return &azure.ListVirtualNodeGroupsInput{OceanID: spotinst.String(oceanID)}
+}
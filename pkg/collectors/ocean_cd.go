@@ -0,0 +1,420 @@
+package collectors
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/oceancd"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/spotinst/spotinst-sdk-go/spotinst/client"
+)
+
+// OceanCDClient is the interface for listing Ocean CD rollout specs and
+// strategies.
+//
+// It is implemented by the Spotinst *oceancd.ServiceOp client.
+type OceanCDClient interface {
+	ListRolloutSpecs(context.Context) (*oceancd.ListRolloutSpecsOutput, error)
+	ListStrategies(context.Context) (*oceancd.ListStrategiesOutput, error)
+}
+
+// Values of the `type` label of Ocean CD strategies.
+const (
+	strategyCanary        = "canary"
+	strategyRollingUpdate = "rolling_update"
+)
+
+// Normalized values of the `phase` label of Ocean CD rollouts.
+const (
+	rolloutPhasePending    = "pending"
+	rolloutPhaseInProgress = "in_progress"
+	rolloutPhasePaused     = "paused"
+	rolloutPhaseFinished   = "finished"
+	rolloutPhaseFailed     = "failed"
+	rolloutPhaseAborted    = "aborted"
+)
+
+// rolloutPhases are the phases which are always exported, so that alerts can
+// rely on explicit zeros.
+var rolloutPhases = []string{
+	rolloutPhasePending,
+	rolloutPhaseInProgress,
+	rolloutPhasePaused,
+	rolloutPhaseFinished,
+	rolloutPhaseFailed,
+	rolloutPhaseAborted,
+}
+
+// rolloutsWindow is the time span of the rollouts which are listed on every
+// collection. Failed and aborted rollouts are only counted if they were
+// updated within the window.
+const rolloutsWindow = 24 * time.Hour
+
+var camelCaseRegex = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// normalizeRolloutPhase converts the phase of a rollout as returned by the
+// Spotinst API, e.g. "InProgress" or "FAILED", into the normalized value of
+// the `phase` label.
+func normalizeRolloutPhase(phase string) string {
+	phase = camelCaseRegex.ReplaceAllString(phase, "${1}_${2}")
+
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(phase))
+}
+
+// oceanCDRollout is a rollout of Ocean CD as returned by the rollouts endpoint
+// of the Spotinst API, which is not covered by the Spotinst SDK.
+type oceanCDRollout struct {
+	ID             string                        `json:"id"`
+	Phase          string                        `json:"phase"`
+	SpotDeployment *oceanCDRolloutSpotDeployment `json:"spotDeployment"`
+	Verifications  []*oceanCDRolloutVerification `json:"verifications"`
+	CreatedAt      *time.Time                    `json:"createdAt"`
+	UpdatedAt      *time.Time                    `json:"updatedAt"`
+}
+
+type oceanCDRolloutSpotDeployment struct {
+	ClusterID string `json:"clusterId"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type oceanCDRolloutVerification struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+}
+
+// oceanCDDeploymentKey identifies the deployment (microservice) of an
+// environment which is rolled out.
+type oceanCDDeploymentKey struct {
+	clusterID  string
+	namespace  string
+	deployment string
+}
+
+func (k oceanCDDeploymentKey) labelValues() []string {
+	return []string{k.clusterID, k.namespace, k.deployment}
+}
+
+// oceanCDRolloutCounters holds the failed and aborted rollouts of a
+// deployment.
+type oceanCDRolloutCounters struct {
+	failed  float64
+	aborted float64
+}
+
+// oceanCDRolloutsState holds the rollout counters of all deployments and the
+// rollouts which were already counted.
+type oceanCDRolloutsState struct {
+	counters map[oceanCDDeploymentKey]*oceanCDRolloutCounters
+	// seen holds the IDs of the counted rollouts with the time they were
+	// last updated.
+	seen map[string]time.Time
+}
+
+// update counts the rollout if it failed or was aborted after cutoff and was
+// not counted before.
+func (s *oceanCDRolloutsState) update(key oceanCDDeploymentKey, rollout *oceanCDRollout, cutoff time.Time) {
+	counters, ok := s.counters[key]
+	if !ok {
+		counters = &oceanCDRolloutCounters{}
+		s.counters[key] = counters
+	}
+
+	phase := normalizeRolloutPhase(rollout.Phase)
+	if phase != rolloutPhaseFailed && phase != rolloutPhaseAborted {
+		return
+	}
+
+	updatedAt := spotinst.TimeValue(rollout.UpdatedAt)
+	if _, ok := s.seen[rollout.ID]; ok || updatedAt.Before(cutoff) {
+		return
+	}
+
+	s.seen[rollout.ID] = updatedAt
+
+	if phase == rolloutPhaseFailed {
+		counters.failed++
+	} else {
+		counters.aborted++
+	}
+}
+
+// prune removes the rollouts which were updated before cutoff, as they are
+// not counted anymore anyway.
+func (s *oceanCDRolloutsState) prune(cutoff time.Time) {
+	for id, updatedAt := range s.seen {
+		if updatedAt.Before(cutoff) {
+			delete(s.seen, id)
+		}
+	}
+}
+
+// OceanCDCollector is a prometheus collector for the progressive delivery
+// configuration of Ocean CD and the rollouts of the deployments it manages.
+//
+// The configuration is listed on every collection, as are the rollouts of
+// the last 24 hours. The rollouts are fetched from the Spotinst API directly,
+// as the Spotinst SDK does not cover them. The failed and aborted rollouts
+// counters are kept in memory and each rollout is only counted once.
+type OceanCDCollector struct {
+	ctx                   context.Context
+	logger                logr.Logger
+	client                OceanCDClient
+	apiClient             SpotinstAPIClient
+	now                   func() time.Time
+	mu                    sync.Mutex
+	state                 *oceanCDRolloutsState
+	rolloutSpecInfo       *prometheus.Desc
+	strategySteps         *prometheus.Desc
+	strategyVerifiedSteps *prometheus.Desc
+	rolloutPhase          *prometheus.Desc
+	verifications         *prometheus.Desc
+	rolloutsFailed        *prometheus.Desc
+	rolloutsAborted       *prometheus.Desc
+}
+
+// NewOceanCDCollector creates a new OceanCDCollector for collecting the Ocean
+// CD configuration and rollouts of the account.
+func NewOceanCDCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanCDClient,
+	apiClient SpotinstAPIClient,
+) *OceanCDCollector {
+	deploymentLabelNames := []string{"controller_cluster_id", "namespace", "deployment"}
+
+	collector := &OceanCDCollector{
+		ctx:       ctx,
+		logger:    logger,
+		client:    client,
+		apiClient: apiClient,
+		now:       time.Now,
+		state: &oceanCDRolloutsState{
+			counters: make(map[oceanCDDeploymentKey]*oceanCDRolloutCounters),
+			seen:     make(map[string]time.Time),
+		},
+		rolloutSpecInfo: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "rollout_spec_info"),
+			"Information about an ocean cd rollout spec and the deployment it applies to",
+			[]string{"rollout_spec", "strategy", "controller_cluster_id", "namespace", "deployment"},
+			nil,
+		),
+		strategySteps: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "strategy_steps"),
+			"The number of steps of an ocean cd strategy",
+			[]string{"strategy", "type"},
+			nil,
+		),
+		strategyVerifiedSteps: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "strategy_verification_steps"),
+			"The number of steps of an ocean cd strategy which run a verification",
+			[]string{"strategy", "type"},
+			nil,
+		),
+		rolloutPhase: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "rollout_phase"),
+			"Whether the latest ocean cd rollout of a deployment is in a phase",
+			append(deploymentLabelNames, "phase"),
+			nil,
+		),
+		verifications: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "rollout_verifications"),
+			"The number of verifications of the latest ocean cd rollout of a deployment per result",
+			append(deploymentLabelNames, "result"),
+			nil,
+		),
+		rolloutsFailed: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "rollouts_failed_total"),
+			"The number of failed ocean cd rollouts of a deployment",
+			deploymentLabelNames,
+			nil,
+		),
+		rolloutsAborted: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_cd", "rollouts_aborted_total"),
+			"The number of aborted ocean cd rollouts of a deployment",
+			deploymentLabelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanCDCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.rolloutSpecInfo
+	ch <- c.strategySteps
+	ch <- c.strategyVerifiedSteps
+	ch <- c.rolloutPhase
+	ch <- c.verifications
+	ch <- c.rolloutsFailed
+	ch <- c.rolloutsAborted
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanCDCollector) Collect(ch chan<- prometheus.Metric) {
+	rolloutSpecs, err := c.client.ListRolloutSpecs(c.ctx)
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean cd rollout specs")
+	} else {
+		c.collectRolloutSpecs(ch, rolloutSpecs.RolloutSpecs)
+	}
+
+	strategies, err := c.client.ListStrategies(c.ctx)
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean cd strategies")
+	} else {
+		c.collectStrategies(ch, strategies.Strategies)
+	}
+
+	c.collectRollouts(ch)
+}
+
+func (c *OceanCDCollector) collectRollouts(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UTC()
+	cutoff := now.Add(-rolloutsWindow)
+
+	request := client.NewRequest(http.MethodGet, "/ocean/cd/rollout")
+	request.Params.Set("fromDate", cutoff.Format(time.RFC3339))
+	request.Params.Set("toDate", now.Format(time.RFC3339))
+
+	rollouts, err := listItems[oceanCDRollout](c.ctx, c.apiClient, request)
+	if err != nil {
+		// The counters collected so far are still exported in order to
+		// avoid gaps.
+		c.logger.Error(err, "failed to list ocean cd rollouts")
+	} else {
+		c.collectLatestRollouts(ch, rollouts, cutoff)
+	}
+
+	for key, counters := range c.state.counters {
+		collectCounterValue(ch, c.rolloutsFailed, counters.failed, key.labelValues())
+		collectCounterValue(ch, c.rolloutsAborted, counters.aborted, key.labelValues())
+	}
+}
+
+// collectLatestRollouts counts the failed and aborted rollouts and collects
+// the phase and verification results of the latest rollout of each
+// deployment.
+func (c *OceanCDCollector) collectLatestRollouts(ch chan<- prometheus.Metric, rollouts []*oceanCDRollout, cutoff time.Time) {
+	latest := make(map[oceanCDDeploymentKey]*oceanCDRollout)
+
+	for _, rollout := range rollouts {
+		var key oceanCDDeploymentKey
+		if target := rollout.SpotDeployment; target != nil {
+			key = oceanCDDeploymentKey{clusterID: target.ClusterID, namespace: target.Namespace, deployment: target.Name}
+		}
+
+		c.state.update(key, rollout, cutoff)
+
+		if current, ok := latest[key]; !ok ||
+			spotinst.TimeValue(rollout.CreatedAt).After(spotinst.TimeValue(current.CreatedAt)) {
+			latest[key] = rollout
+		}
+	}
+
+	c.state.prune(cutoff)
+
+	for key, rollout := range latest {
+		labelValues := key.labelValues()
+
+		c.collectRolloutPhase(ch, normalizeRolloutPhase(rollout.Phase), labelValues)
+
+		results := make(map[string]float64)
+		for _, verification := range rollout.Verifications {
+			results[normalizeRolloutPhase(verification.Phase)]++
+		}
+
+		for result, count := range results {
+			collectGaugeValue(ch, c.verifications, count, append(labelValues[:len(labelValues):len(labelValues)], result))
+		}
+	}
+}
+
+// collectRolloutPhase collects the known phases of a rollout, including the
+// current phase if it is not one of them.
+func (c *OceanCDCollector) collectRolloutPhase(ch chan<- prometheus.Metric, current string, labelValues []string) {
+	known := false
+
+	for _, phase := range rolloutPhases {
+		value := 0.0
+		if phase == current {
+			value = 1
+			known = true
+		}
+
+		collectGaugeValue(ch, c.rolloutPhase, value, append(labelValues[:len(labelValues):len(labelValues)], phase))
+	}
+
+	if !known {
+		collectGaugeValue(ch, c.rolloutPhase, 1, append(labelValues[:len(labelValues):len(labelValues)], current))
+	}
+}
+
+func (c *OceanCDCollector) collectRolloutSpecs(ch chan<- prometheus.Metric, rolloutSpecs []*oceancd.RolloutSpec) {
+	for _, rolloutSpec := range rolloutSpecs {
+		var strategy, clusterID, namespace, deployment string
+
+		if rolloutSpec.Strategy != nil {
+			strategy = spotinst.StringValue(rolloutSpec.Strategy.Name)
+		}
+
+		if target := rolloutSpec.SpotDeployment; target != nil {
+			clusterID = spotinst.StringValue(target.ClusterId)
+			namespace = spotinst.StringValue(target.Namespace)
+			deployment = spotinst.StringValue(target.Name)
+		}
+
+		collectGaugeValue(ch, c.rolloutSpecInfo, 1, []string{
+			spotinst.StringValue(rolloutSpec.Name),
+			strategy,
+			clusterID,
+			namespace,
+			deployment,
+		})
+	}
+}
+
+func (c *OceanCDCollector) collectStrategies(ch chan<- prometheus.Metric, strategies []*oceancd.Strategy) {
+	for _, strategy := range strategies {
+		name := spotinst.StringValue(strategy.Name)
+
+		if canary := strategy.Canary; canary != nil {
+			var verified int
+
+			for _, step := range canary.Steps {
+				if step.Verification != nil {
+					verified++
+				}
+			}
+
+			c.collectStrategySteps(ch, len(canary.Steps), verified, []string{name, strategyCanary})
+		}
+
+		if rollingUpdate := strategy.RollingUpdate; rollingUpdate != nil {
+			var verified int
+
+			for _, step := range rollingUpdate.Steps {
+				if step.Verification != nil {
+					verified++
+				}
+			}
+
+			c.collectStrategySteps(ch, len(rollingUpdate.Steps), verified, []string{name, strategyRollingUpdate})
+		}
+	}
+}
+
+func (c *OceanCDCollector) collectStrategySteps(ch chan<- prometheus.Metric, steps, verified int, labelValues []string) {
+	collectGaugeValue(ch, c.strategySteps, float64(steps), labelValues)
+	collectGaugeValue(ch, c.strategyVerifiedSteps, float64(verified), labelValues)
+}
@@ -0,0 +1,259 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/oceancd"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanCDClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanCDClient) ListRolloutSpecs(ctx context.Context) (*oceancd.ListRolloutSpecsOutput, error) {
+	args := m.Called(ctx)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*oceancd.ListRolloutSpecsOutput), args.Error(1)
+}
+
+func (m *mockOceanCDClient) ListStrategies(ctx context.Context) (*oceancd.ListStrategiesOutput, error) {
+	args := m.Called(ctx)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*oceancd.ListStrategiesOutput), args.Error(1)
+}
+
+func TestOceanCDCollector(t *testing.T) {
+	rolloutSpecs := &oceancd.ListRolloutSpecsOutput{RolloutSpecs: []*oceancd.RolloutSpec{
+		{
+			Name: spotinst.String("foo-prod"),
+			SpotDeployment: &oceancd.SpotDeployment{
+				ClusterId: spotinst.String("prod"),
+				Namespace: spotinst.String("foo-ns"),
+				Name:      spotinst.String("foo-deployment"),
+			},
+			Strategy: &oceancd.RolloutSpecStrategy{Name: spotinst.String("canary-3-steps")},
+		},
+	}}
+
+	strategies := &oceancd.ListStrategiesOutput{Strategies: []*oceancd.Strategy{
+		{
+			Name: spotinst.String("canary-3-steps"),
+			Canary: &oceancd.Canary{Steps: []*oceancd.CanarySteps{
+				{Name: spotinst.String("10%")},
+				{Name: spotinst.String("50%"), Verification: &oceancd.Verification{TemplateNames: []string{"error-rate"}}},
+				{Name: spotinst.String("100%")},
+			}},
+		},
+		{
+			Name: spotinst.String("rolling"),
+			RollingUpdate: &oceancd.RollingUpdate{Steps: []*oceancd.RollingUpdateSteps{
+				{Name: spotinst.String("all")},
+			}},
+		},
+	}}
+
+	testCases := []struct {
+		name     string
+		client   func() OceanCDClient
+		expected string
+	}{
+		{
+			name: "failing clients",
+			client: func() OceanCDClient {
+				mockClient := new(mockOceanCDClient)
+				mockClient.On("ListRolloutSpecs", mock.Anything).Return(nil, errors.New("error"))
+				mockClient.On("ListStrategies", mock.Anything).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "failing strategies",
+			client: func() OceanCDClient {
+				mockClient := new(mockOceanCDClient)
+				mockClient.On("ListRolloutSpecs", mock.Anything).Return(rolloutSpecs, nil)
+				mockClient.On("ListStrategies", mock.Anything).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_cd_rollout_spec_info Information about an ocean cd rollout spec and the deployment it applies to
+                # TYPE spotinst_ocean_cd_rollout_spec_info gauge
+                spotinst_ocean_cd_rollout_spec_info{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",rollout_spec="foo-prod",strategy="canary-3-steps"} 1
+            `,
+		},
+		{
+			name: "rollout specs and strategies",
+			client: func() OceanCDClient {
+				mockClient := new(mockOceanCDClient)
+				mockClient.On("ListRolloutSpecs", mock.Anything).Return(rolloutSpecs, nil)
+				mockClient.On("ListStrategies", mock.Anything).Return(strategies, nil)
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_cd_rollout_spec_info Information about an ocean cd rollout spec and the deployment it applies to
+                # TYPE spotinst_ocean_cd_rollout_spec_info gauge
+                spotinst_ocean_cd_rollout_spec_info{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",rollout_spec="foo-prod",strategy="canary-3-steps"} 1
+                # HELP spotinst_ocean_cd_strategy_steps The number of steps of an ocean cd strategy
+                # TYPE spotinst_ocean_cd_strategy_steps gauge
+                spotinst_ocean_cd_strategy_steps{strategy="canary-3-steps",type="canary"} 3
+                spotinst_ocean_cd_strategy_steps{strategy="rolling",type="rolling_update"} 1
+                # HELP spotinst_ocean_cd_strategy_verification_steps The number of steps of an ocean cd strategy which run a verification
+                # TYPE spotinst_ocean_cd_strategy_verification_steps gauge
+                spotinst_ocean_cd_strategy_verification_steps{strategy="canary-3-steps",type="canary"} 1
+                spotinst_ocean_cd_strategy_verification_steps{strategy="rolling",type="rolling_update"} 0
+            `,
+		},
+	}
+
+	// Rollouts are covered by TestOceanCDCollectorRollouts.
+	apiClient := newTestAPIClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanCDCollector(ctx, logger, testCase.client(), apiClient)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func TestOceanCDCollectorRollouts(t *testing.T) {
+	start := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	deployment := &oceanCDRolloutSpotDeployment{ClusterID: "prod", Namespace: "foo-ns", Name: "foo-deployment"}
+	rollout := func(id, phase string, createdAt time.Time, verifications ...*oceanCDRolloutVerification) oceanCDRollout {
+		return oceanCDRollout{
+			ID:             id,
+			Phase:          phase,
+			SpotDeployment: deployment,
+			Verifications:  verifications,
+			CreatedAt:      spotinst.Time(createdAt),
+			UpdatedAt:      spotinst.Time(createdAt.Add(10 * time.Minute)),
+		}
+	}
+
+	responses := [][]any{
+		{
+			// Failed before the window, so it is not counted.
+			rollout("rol-1", "Failed", start.Add(-48*time.Hour)),
+			rollout("rol-2", "Aborted", start.Add(-2*time.Hour)),
+			rollout("rol-3", "InProgress", start.Add(-time.Hour),
+				&oceanCDRolloutVerification{Name: "error-rate", Phase: "Successful"},
+				&oceanCDRolloutVerification{Name: "latency", Phase: "Running"},
+			),
+		},
+		{
+			rollout("rol-2", "Aborted", start.Add(-2*time.Hour)),
+			rollout("rol-3", "Failed", start.Add(-time.Hour),
+				&oceanCDRolloutVerification{Name: "error-rate", Phase: "Successful"},
+				&oceanCDRolloutVerification{Name: "latency", Phase: "Failed"},
+			),
+		},
+	}
+
+	var calls atomic.Int32
+
+	apiClient := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ocean/cd/rollout", r.URL.Path)
+		assert.Equal(t, "2026-01-01T12:00:00Z", r.URL.Query().Get("fromDate"))
+		assert.Equal(t, "2026-01-02T12:00:00Z", r.URL.Query().Get("toDate"))
+
+		call := int(calls.Add(1)) - 1
+		if call >= len(responses) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeAPIItems(w, responses[call]...)
+	})
+
+	mockClient := new(mockOceanCDClient)
+	mockClient.On("ListRolloutSpecs", mock.Anything).Return(&oceancd.ListRolloutSpecsOutput{}, nil)
+	mockClient.On("ListStrategies", mock.Anything).Return(&oceancd.ListStrategiesOutput{}, nil)
+
+	logger := zapr.NewLogger(zap.NewNop())
+	collector := NewOceanCDCollector(context.Background(), logger, mockClient, apiClient)
+	collector.now = func() time.Time { return start }
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+        # HELP spotinst_ocean_cd_rollout_phase Whether the latest ocean cd rollout of a deployment is in a phase
+        # TYPE spotinst_ocean_cd_rollout_phase gauge
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="aborted"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="failed"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="finished"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="in_progress"} 1
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="paused"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="pending"} 0
+        # HELP spotinst_ocean_cd_rollout_verifications The number of verifications of the latest ocean cd rollout of a deployment per result
+        # TYPE spotinst_ocean_cd_rollout_verifications gauge
+        spotinst_ocean_cd_rollout_verifications{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",result="running"} 1
+        spotinst_ocean_cd_rollout_verifications{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",result="successful"} 1
+        # HELP spotinst_ocean_cd_rollouts_aborted_total The number of aborted ocean cd rollouts of a deployment
+        # TYPE spotinst_ocean_cd_rollouts_aborted_total counter
+        spotinst_ocean_cd_rollouts_aborted_total{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns"} 1
+        # HELP spotinst_ocean_cd_rollouts_failed_total The number of failed ocean cd rollouts of a deployment
+        # TYPE spotinst_ocean_cd_rollouts_failed_total counter
+        spotinst_ocean_cd_rollouts_failed_total{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns"} 0
+    `)))
+
+	// Rollouts are only counted once.
+	expectedCounters := `
+        # HELP spotinst_ocean_cd_rollouts_aborted_total The number of aborted ocean cd rollouts of a deployment
+        # TYPE spotinst_ocean_cd_rollouts_aborted_total counter
+        spotinst_ocean_cd_rollouts_aborted_total{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns"} 1
+        # HELP spotinst_ocean_cd_rollouts_failed_total The number of failed ocean cd rollouts of a deployment
+        # TYPE spotinst_ocean_cd_rollouts_failed_total counter
+        spotinst_ocean_cd_rollouts_failed_total{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns"} 1
+    `
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+        # HELP spotinst_ocean_cd_rollout_phase Whether the latest ocean cd rollout of a deployment is in a phase
+        # TYPE spotinst_ocean_cd_rollout_phase gauge
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="aborted"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="failed"} 1
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="finished"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="in_progress"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="paused"} 0
+        spotinst_ocean_cd_rollout_phase{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",phase="pending"} 0
+        # HELP spotinst_ocean_cd_rollout_verifications The number of verifications of the latest ocean cd rollout of a deployment per result
+        # TYPE spotinst_ocean_cd_rollout_verifications gauge
+        spotinst_ocean_cd_rollout_verifications{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",result="failed"} 1
+        spotinst_ocean_cd_rollout_verifications{controller_cluster_id="prod",deployment="foo-deployment",namespace="foo-ns",result="successful"} 1
+    `+expectedCounters)))
+
+	// The counters are still exported if listing the rollouts fails.
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expectedCounters)))
+}
+
+func TestNormalizeRolloutPhase(t *testing.T) {
+	assert.Equal(t, "in_progress", normalizeRolloutPhase("InProgress"))
+	assert.Equal(t, "in_progress", normalizeRolloutPhase("IN_PROGRESS"))
+	assert.Equal(t, "in_progress", normalizeRolloutPhase("in progress"))
+	assert.Equal(t, "failed", normalizeRolloutPhase("Failed"))
+}
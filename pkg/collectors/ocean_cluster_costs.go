@@ -0,0 +1,231 @@
+package collectors
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/mcs"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanClusterCostsClient is the interface for fetching Ocean cluster costs.
+// Costs are looked up by the controller cluster ID, so the client is not
+// specific to AWS and is used for clusters of all cloud providers.
+//
+// It is implemented by the Spotinst *mcs.ServiceOp client.
+type OceanClusterCostsClient interface {
+	GetClusterCosts(context.Context, *mcs.ClusterCostInput) (*mcs.ClusterCostOutput, error)
+}
+
+// oceanClusterCostsCollector collects the costs of Ocean clusters. It
+// implements the cost collectors of all cloud providers, which only differ in
+// the way clusters are discovered.
+type oceanClusterCostsCollector struct {
+	ctx      context.Context
+	logger   logr.Logger
+	client   OceanClusterCostsClient
+	clusters []oceanCluster
+	owners   workloadOwnerResolver
+	emitter  costEmitter
+}
+
+func newOceanClusterCostsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	cloud string,
+	client OceanClusterCostsClient,
+	clusters []oceanCluster,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+	rollupWorkloads bool,
+) oceanClusterCostsCollector {
+	if metadata == nil {
+		metadata = noopMetadataProvider{}
+	}
+
+	return oceanClusterCostsCollector{
+		ctx:      ctx,
+		logger:   logger,
+		client:   client,
+		clusters: clusters,
+		owners:   workloadOwnerResolver{enabled: rollupWorkloads, metadata: metadata},
+		emitter:  newCostEmitter(oceanSubsystem(cloud), labelResolver, clusterTagMappings, metadata),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *oceanClusterCostsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.emitter.describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *oceanClusterCostsCollector) Collect(ch chan<- prometheus.Metric) {
+	fromDate, toDate := currentMonth(time.Now())
+
+	for _, cluster := range c.clusters {
+		input := &mcs.ClusterCostInput{
+			ClusterID: spotinst.String(cluster.controllerClusterID),
+			FromDate:  fromDate,
+			ToDate:    toDate,
+		}
+
+		output, err := c.client.GetClusterCosts(c.ctx, input)
+		if err != nil {
+			c.logger.Error(err, "failed to fetch cluster costs", "ocean_id", cluster.id)
+			continue
+		}
+
+		records := costRecordsFromMCS(c.owners, cluster.controllerClusterID, output.ClusterCosts)
+
+		c.emitter.emit(ch, cluster, records)
+	}
+}
+
+// costRecordsFromMCS translates the cluster costs returned by the Spotinst
+// MCS API into cost records. Jobs are rolled up into their CronJobs if
+// possible and resources with high cardinality names are aggregated.
+func costRecordsFromMCS(
+	owners workloadOwnerResolver,
+	clusterID string,
+	clusterCosts []*mcs.ClusterCost,
+) []clusterCostRecord {
+	records := make([]clusterCostRecord, 0, len(clusterCosts))
+
+	for _, clusterCost := range clusterCosts {
+		record := clusterCostRecord{cost: spotinst.Float64Value(clusterCost.TotalCost)}
+
+		for _, namespace := range clusterCost.Namespaces {
+			namespaceName := spotinst.StringValue(namespace.Namespace)
+			cronJobs, jobs := rollUpJobs(owners, clusterID, namespaceName, namespace.Jobs)
+
+			namespaceRecord := namespaceCostRecord{
+				namespace: namespaceName,
+				cost:      spotinst.Float64Value(namespace.Cost),
+				labels:    namespace.Labels,
+			}
+
+			for _, workloads := range []struct {
+				workload  string
+				resources []*mcs.Resource
+			}{
+				{workloadDeployment, namespace.Deployments},
+				{workloadDaemonSet, namespace.DaemonSets},
+				{workloadStatefulSet, namespace.StatefulSets},
+				{workloadJob, jobs},
+				{workloadCronJob, cronJobs},
+			} {
+				for _, resource := range aggregateHighCardinalityResources(workloads.resources) {
+					namespaceRecord.workloads = append(namespaceRecord.workloads, workloadCostRecord{
+						workload: workloads.workload,
+						name:     spotinst.StringValue(resource.Name),
+						cost:     spotinst.Float64Value(resource.Cost),
+						labels:   resource.Labels,
+					})
+				}
+			}
+
+			record.namespaces = append(record.namespaces, namespaceRecord)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// rollUpJobs splits the jobs into the ones which can be rolled up into their
+// owning CronJobs and the remaining ones. The costs of Jobs belonging to the
+// same CronJob are summed up.
+func rollUpJobs(
+	owners workloadOwnerResolver,
+	clusterID string,
+	namespace string,
+	resources []*mcs.Resource,
+) (cronJobs []*mcs.Resource, jobs []*mcs.Resource) {
+	cronJobMap := make(map[string]*mcs.Resource)
+
+	for _, resource := range resources {
+		workload, name := owners.resolve(clusterID, namespace, workloadJob, spotinst.StringValue(resource.Name))
+		if workload != workloadCronJob {
+			jobs = append(jobs, resource)
+			continue
+		}
+
+		if existing, ok := cronJobMap[name]; ok {
+			existing.Cost = spotinst.Float64(spotinst.Float64Value(existing.Cost) + spotinst.Float64Value(resource.Cost))
+			continue
+		}
+
+		cronJob := &mcs.Resource{
+			Name:      spotinst.String(name),
+			Namespace: resource.Namespace,
+			Cost:      resource.Cost,
+			Labels:    resource.Labels,
+		}
+
+		cronJobMap[name] = cronJob
+		cronJobs = append(cronJobs, cronJob)
+	}
+
+	return cronJobs, jobs
+}
+
+// currentMonth returns the first day of the month of now and the first day of
+// the following month, formatted for use in cost queries.
+func currentMonth(now time.Time) (*string, *string) {
+	firstDayOfCurrentMonth := now.AddDate(0, 0, -now.Day()+1)
+	firstDayOfNextMonth := now.AddDate(0, 1, -now.Day()+1)
+
+	return spotinst.String(firstDayOfCurrentMonth.Format("2006-01-02")),
+		spotinst.String(firstDayOfNextMonth.Format("2006-01-02"))
+}
+
+// Matches timestamps and UUIDs.
+var uuidRegex = regexp.MustCompile(`[0-9]{8}|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// AggregateHighCardinalityResources removes timestamps and UUIDs from resource
+// names and aggregates the costs for these. The resources are not modified, as
+// they may be shared with other collectors.
+//
+// UUIDs and timestamps would cause high metric cardinality and will negatively
+// affect performance and storage usage of the metrics engine that will consume
+// the metrics. This function is a best-effort to avoid this.
+func aggregateHighCardinalityResources(resources []*mcs.Resource) []*mcs.Resource {
+	resourceMap := make(map[string]*mcs.Resource, len(resources))
+	cleaned := make([]*mcs.Resource, 0, len(resources))
+
+	for _, resource := range resources {
+		name := normalizeResourceName(spotinst.StringValue(resource.Name))
+
+		// Sum the costs for existing resources.
+		if existing, ok := resourceMap[name]; ok {
+			existing.Cost = spotinst.Float64(spotinst.Float64Value(existing.Cost) + spotinst.Float64Value(resource.Cost))
+			continue
+		}
+
+		aggregated := *resource
+		aggregated.Name = spotinst.String(name)
+
+		resourceMap[name] = &aggregated
+		cleaned = append(cleaned, &aggregated)
+	}
+
+	return cleaned
+}
+
+// normalizeResourceName removes timestamps and UUIDs from a resource name.
+func normalizeResourceName(name string) string {
+	normalized := uuidRegex.ReplaceAllString(name, "")
+	if normalized == name {
+		return name
+	}
+
+	// Remove hyphens that might be left over after removing the timestamps/UUIDs.
+	return strings.Trim(strings.ReplaceAll(normalized, "--", "-"), "-")
+}
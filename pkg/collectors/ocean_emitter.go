@@ -0,0 +1,439 @@
+package collectors
+
+import (
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// costEmitter emits the cost records of Ocean clusters of any cloud provider.
+// It is also used to emit cost-like values with the same structure, e.g. the
+// potential savings of applying resource suggestions.
+type costEmitter struct {
+	labelResolver      labels.Resolver
+	clusterTagMappings labels.Mappings
+	metadata           KubernetesMetadataProvider
+	clusterCost        *prometheus.Desc
+	namespaceCost      *prometheus.Desc
+	workloadCost       *prometheus.Desc
+}
+
+// costMetrics holds the names and help texts of the metrics emitted by a
+// costEmitter.
+type costMetrics struct {
+	cluster, clusterHelp     string
+	namespace, namespaceHelp string
+	workload, workloadHelp   string
+}
+
+// newCostEmitter creates a new costEmitter for the metrics of the provided
+// subsystem. The clusterTagMappings are used to propagate Ocean cluster tags
+// onto the cluster-level metrics, the labelResolver maps the labels of
+// namespaces and workloads. The metadata must not be nil.
+func newCostEmitter(
+	subsystem string,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+) costEmitter {
+	return newCostEmitterWithMetrics(subsystem, labelResolver, clusterTagMappings, metadata, costMetrics{
+		cluster:       "cluster_cost",
+		clusterHelp:   "Total cost of an ocean cluster",
+		namespace:     "namespace_cost",
+		namespaceHelp: "Total cost of a namespace",
+		workload:      "workload_cost",
+		workloadHelp:  "Total cost of a workload",
+	})
+}
+
+// newSavingsEmitter creates a new costEmitter for the potential savings of
+// applying resource suggestions. The arguments behave like the ones of
+// newCostEmitter.
+func newSavingsEmitter(
+	subsystem string,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+) costEmitter {
+	return newCostEmitterWithMetrics(subsystem, labelResolver, clusterTagMappings, metadata, costMetrics{
+		cluster:       "cluster_rightsizing_potential_savings",
+		clusterHelp:   "Estimated cost that could have been saved in an ocean cluster by applying the resource suggestions",
+		namespace:     "namespace_rightsizing_potential_savings",
+		namespaceHelp: "Estimated cost that could have been saved in a namespace by applying the resource suggestions",
+		workload:      "workload_rightsizing_potential_savings",
+		workloadHelp:  "Estimated cost that could have been saved for a workload by applying the resource suggestions",
+	})
+}
+
+func newCostEmitterWithMetrics(
+	subsystem string,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+	metrics costMetrics,
+) costEmitter {
+	return costEmitter{
+		labelResolver:      labelResolver,
+		clusterTagMappings: clusterTagMappings,
+		metadata:           metadata,
+		clusterCost: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, metrics.cluster),
+			metrics.clusterHelp,
+			append(oceanClusterLabelNames(), clusterTagMappings.LabelNames()...),
+			nil,
+		),
+		namespaceCost: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, metrics.namespace),
+			metrics.namespaceHelp,
+			append(append(oceanClusterLabelNames(), "namespace"), labelResolver.LabelNames()...),
+			nil,
+		),
+		workloadCost: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, metrics.workload),
+			metrics.workloadHelp,
+			append(append(oceanClusterLabelNames(), "namespace", "name", "workload"), labelResolver.LabelNames()...),
+			nil,
+		),
+	}
+}
+
+func (e costEmitter) describe(ch chan<- *prometheus.Desc) {
+	ch <- e.clusterCost
+	ch <- e.namespaceCost
+	ch <- e.workloadCost
+}
+
+func (e costEmitter) emit(ch chan<- prometheus.Metric, cluster oceanCluster, records []clusterCostRecord) {
+	labelValues := cluster.labelValues()
+	clusterLabelValues := append(labelValues, e.clusterTagMappings.LabelValues(cluster.tags)...)
+	labelSets := labels.Sets{labels.SourceCluster: cluster.tags}
+
+	for _, record := range records {
+		collectGaugeValue(ch, e.clusterCost, record.cost, clusterLabelValues)
+
+		for _, namespace := range record.namespaces {
+			e.emitNamespace(ch, namespace, cluster.controllerClusterID, labelValues, labelSets)
+		}
+	}
+}
+
+func (e costEmitter) emitNamespace(
+	ch chan<- prometheus.Metric,
+	namespace namespaceCostRecord,
+	clusterID string,
+	clusterLabelValues []string,
+	clusterLabelSets labels.Sets,
+) {
+	labelSets := labels.Sets{
+		labels.SourceNamespace: mergeLabels(e.metadata.NamespaceMetadata(clusterID, namespace.namespace), namespace.labels),
+		labels.SourceCluster:   clusterLabelSets[labels.SourceCluster],
+	}
+
+	labelValues := append(clusterLabelValues, namespace.namespace)
+	namespaceLabelValues := append(labelValues, e.labelResolver.LabelValues(labels.SourceNamespace, labelSets)...)
+
+	collectGaugeValue(ch, e.namespaceCost, namespace.cost, namespaceLabelValues)
+
+	for _, workload := range namespace.workloads {
+		metadata := e.metadata.WorkloadMetadata(clusterID, namespace.namespace, workload.workload, workload.name)
+
+		workloadLabelSets := labels.Sets{
+			labels.SourceResource:  mergeLabels(metadata, workload.labels),
+			labels.SourceNamespace: labelSets[labels.SourceNamespace],
+			labels.SourceCluster:   labelSets[labels.SourceCluster],
+		}
+
+		workloadLabelValues := append(labelValues, workload.name, workload.workload)
+		workloadLabelValues = append(workloadLabelValues, e.labelResolver.LabelValues(labels.SourceResource, workloadLabelSets)...)
+
+		collectGaugeValue(ch, e.workloadCost, workload.cost, workloadLabelValues)
+	}
+}
+
+// suggestionEmitter emits the resource suggestion records of Ocean clusters
+// of any cloud provider.
+type suggestionEmitter struct {
+	labelResolver                 labels.Resolver
+	metadata                      KubernetesMetadataProvider
+	tolerance                     float64
+	requestedWorkloadCPU          *prometheus.Desc
+	suggestedWorkloadCPU          *prometheus.Desc
+	requestedWorkloadMemory       *prometheus.Desc
+	suggestedWorkloadMemory       *prometheus.Desc
+	requestedContainerCPU         *prometheus.Desc
+	suggestedContainerCPU         *prometheus.Desc
+	requestedContainerMemory      *prometheus.Desc
+	suggestedContainerMemory      *prometheus.Desc
+	containerCPULimit             *prometheus.Desc
+	suggestedContainerCPULimit    *prometheus.Desc
+	containerMemoryLimit          *prometheus.Desc
+	suggestedContainerMemoryLimit *prometheus.Desc
+	cpuOverprovisioning           *prometheus.Desc
+	memoryOverprovisioning        *prometheus.Desc
+	overprovisionedWorkloads      *prometheus.Desc
+}
+
+// newSuggestionEmitter creates a new suggestionEmitter for the metrics of the
+// provided subsystem. The labelResolver maps the labels of workloads, the
+// tolerance is used to classify workloads as over- or under-provisioned. The
+// metadata must not be nil.
+func newSuggestionEmitter(
+	subsystem string,
+	labelResolver labels.Resolver,
+	metadata KubernetesMetadataProvider,
+	tolerance float64,
+) suggestionEmitter {
+	workloadLabelNames := append(
+		append(oceanClusterLabelNames(), "workload", "namespace", "name"),
+		labelResolver.LabelNames()...,
+	)
+	ratioLabelNames := append(
+		append(oceanClusterLabelNames(), "workload", "namespace", "name", "direction"),
+		labelResolver.LabelNames()...,
+	)
+	containerLabelNames := append(
+		append(oceanClusterLabelNames(), "workload", "namespace", "name", "container"),
+		labelResolver.LabelNames()...,
+	)
+
+	return suggestionEmitter{
+		labelResolver: labelResolver,
+		metadata:      metadata,
+		tolerance:     tolerance,
+		requestedWorkloadCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_cpu_requested"),
+			"The number of actual CPU units requested by a workload",
+			workloadLabelNames,
+			nil,
+		),
+		suggestedWorkloadCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_cpu_suggested"),
+			"The number of CPU units suggested for a workload",
+			workloadLabelNames,
+			nil,
+		),
+		requestedWorkloadMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_memory_requested"),
+			"The number of actual memory units requested by a workload",
+			workloadLabelNames,
+			nil,
+		),
+		suggestedWorkloadMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_memory_suggested"),
+			"The number of memory units suggested for a workload",
+			workloadLabelNames,
+			nil,
+		),
+		requestedContainerCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_cpu_requested"),
+			"The number of actual CPU units requested by a workload's container",
+			containerLabelNames,
+			nil,
+		),
+		suggestedContainerCPU: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_cpu_suggested"),
+			"The number of CPU units suggested for a workload's container",
+			containerLabelNames,
+			nil,
+		),
+		requestedContainerMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_memory_requested"),
+			"The number of actual memory units requested by a workload's container",
+			containerLabelNames,
+			nil,
+		),
+		suggestedContainerMemory: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_memory_suggested"),
+			"The number of memory units suggested for a workload's container",
+			containerLabelNames,
+			nil,
+		),
+		containerCPULimit: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_cpu_limit"),
+			"The number of CPU units a workload's container is limited to",
+			containerLabelNames,
+			nil,
+		),
+		suggestedContainerCPULimit: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_cpu_limit_suggested"),
+			"The number of CPU units suggested as limit for a workload's container",
+			containerLabelNames,
+			nil,
+		),
+		containerMemoryLimit: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_memory_limit"),
+			"The number of memory units a workload's container is limited to",
+			containerLabelNames,
+			nil,
+		),
+		suggestedContainerMemoryLimit: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_container_memory_limit_suggested"),
+			"The number of memory units suggested as limit for a workload's container",
+			containerLabelNames,
+			nil,
+		),
+		cpuOverprovisioning: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_cpu_overprovisioning_ratio"),
+			"The share of CPU units requested by a workload which exceed the suggestion, negative if under-provisioned",
+			ratioLabelNames,
+			nil,
+		),
+		memoryOverprovisioning: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "workload_memory_overprovisioning_ratio"),
+			"The share of memory units requested by a workload which exceed the suggestion, negative if under-provisioned",
+			ratioLabelNames,
+			nil,
+		),
+		overprovisionedWorkloads: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", subsystem, "namespace_overprovisioned_workloads"),
+			"The number of workloads in a namespace which are over-provisioned for a resource",
+			append(oceanClusterLabelNames(), "namespace", "resource"),
+			nil,
+		),
+	}
+}
+
+func (e suggestionEmitter) describe(ch chan<- *prometheus.Desc) {
+	ch <- e.requestedWorkloadCPU
+	ch <- e.suggestedWorkloadCPU
+	ch <- e.requestedWorkloadMemory
+	ch <- e.suggestedWorkloadMemory
+	ch <- e.requestedContainerCPU
+	ch <- e.suggestedContainerCPU
+	ch <- e.requestedContainerMemory
+	ch <- e.suggestedContainerMemory
+	ch <- e.containerCPULimit
+	ch <- e.suggestedContainerCPULimit
+	ch <- e.containerMemoryLimit
+	ch <- e.suggestedContainerMemoryLimit
+	ch <- e.cpuOverprovisioning
+	ch <- e.memoryOverprovisioning
+	ch <- e.overprovisionedWorkloads
+}
+
+// emit emits the suggestion records of a cluster. The owners of the workloads
+// must already be resolved, see resolveSuggestionOwners.
+func (e suggestionEmitter) emit(ch chan<- prometheus.Metric, cluster oceanCluster, suggestions []workloadSuggestionRecord) {
+	clusterID := cluster.controllerClusterID
+	overprovisioned := make(map[namespaceResource]float64)
+
+	for _, suggestion := range suggestions {
+		labelSets := labels.Sets{
+			labels.SourceResource:  e.metadata.WorkloadMetadata(clusterID, suggestion.namespace, suggestion.workload, suggestion.name),
+			labels.SourceNamespace: e.metadata.NamespaceMetadata(clusterID, suggestion.namespace),
+			labels.SourceCluster:   cluster.tags,
+		}
+
+		labelValues := append(cluster.labelValues(), suggestion.workload, suggestion.namespace, suggestion.name)
+		resolvedLabelValues := e.labelResolver.LabelValues(labels.SourceResource, labelSets)
+		workloadLabelValues := append(labelValues, resolvedLabelValues...)
+
+		collectGaugeValue(ch, e.requestedWorkloadCPU, suggestion.requestedCPU, workloadLabelValues)
+		collectGaugeValue(ch, e.suggestedWorkloadCPU, suggestion.suggestedCPU, workloadLabelValues)
+		collectGaugeValue(ch, e.requestedWorkloadMemory, suggestion.requestedMemory, workloadLabelValues)
+		collectGaugeValue(ch, e.suggestedWorkloadMemory, suggestion.suggestedMemory, workloadLabelValues)
+
+		for _, resource := range []struct {
+			name                 string
+			desc                 *prometheus.Desc
+			requested, suggested float64
+		}{
+			{"cpu", e.cpuOverprovisioning, suggestion.requestedCPU, suggestion.suggestedCPU},
+			{"memory", e.memoryOverprovisioning, suggestion.requestedMemory, suggestion.suggestedMemory},
+		} {
+			ratio, ok := overprovisioningRatio(resource.requested, resource.suggested)
+			if !ok {
+				continue
+			}
+
+			direction := provisioningDirection(ratio, e.tolerance)
+			ratioLabelValues := append(append(labelValues, direction), resolvedLabelValues...)
+
+			collectGaugeValue(ch, resource.desc, ratio, ratioLabelValues)
+
+			// Namespaces without over-provisioned workloads are recorded
+			// as well in order to export explicit zero counts.
+			key := namespaceResource{namespace: suggestion.namespace, resource: resource.name}
+			count := overprovisioned[key]
+			if direction == directionOver {
+				count++
+			}
+
+			overprovisioned[key] = count
+		}
+
+		e.emitContainers(ch, suggestion, clusterID, labelValues, resolvedLabelValues)
+	}
+
+	for key, count := range overprovisioned {
+		collectGaugeValue(ch, e.overprovisionedWorkloads, count, append(cluster.labelValues(), key.namespace, key.resource))
+	}
+}
+
+// namespaceResource identifies a resource type within a namespace.
+type namespaceResource struct {
+	namespace string
+	resource  string
+}
+
+func (e suggestionEmitter) emitContainers(
+	ch chan<- prometheus.Metric,
+	suggestion workloadSuggestionRecord,
+	clusterID string,
+	workloadLabelValues []string,
+	resolvedLabelValues []string,
+) {
+	for _, container := range suggestion.containers {
+		labelValues := append(workloadLabelValues, container.name)
+		labelValues = append(labelValues, resolvedLabelValues...)
+
+		collectGaugeValue(ch, e.requestedContainerCPU, container.requestedCPU, labelValues)
+		collectGaugeValue(ch, e.suggestedContainerCPU, container.suggestedCPU, labelValues)
+		collectGaugeValue(ch, e.requestedContainerMemory, container.requestedMemory, labelValues)
+		collectGaugeValue(ch, e.suggestedContainerMemory, container.suggestedMemory, labelValues)
+
+		// The Spotinst suggestions do not contain limits, so they are taken
+		// from the Kubernetes workload if available.
+		cpuLimit, memoryLimit := e.metadata.ContainerLimits(
+			clusterID,
+			suggestion.namespace,
+			suggestion.workload,
+			suggestion.name,
+			container.name,
+		)
+
+		e.emitContainerLimit(
+			ch, e.containerCPULimit, e.suggestedContainerCPULimit, cpuLimit,
+			container.requestedCPU, container.suggestedCPU,
+			labelValues,
+		)
+		e.emitContainerLimit(
+			ch, e.containerMemoryLimit, e.suggestedContainerMemoryLimit, memoryLimit,
+			container.requestedMemory, container.suggestedMemory,
+			labelValues,
+		)
+	}
+}
+
+// emitContainerLimit emits the limit of a container resource and the
+// suggested limit. The suggested limit scales the limit by the same factor as
+// the suggestion scales the request, preserving the ratio between limit and
+// request. Nothing is emitted for resources without limit, and no suggested
+// limit is emitted for resources without request.
+func (e suggestionEmitter) emitContainerLimit(
+	ch chan<- prometheus.Metric,
+	limitDesc, suggestedLimitDesc *prometheus.Desc,
+	limit, requested, suggested float64,
+	labelValues []string,
+) {
+	if limit <= 0 {
+		return
+	}
+
+	collectGaugeValue(ch, limitDesc, limit, labelValues)
+
+	if requested <= 0 {
+		return
+	}
+
+	collectGaugeValue(ch, suggestedLimitDesc, limit*suggested/requested, labelValues)
+}
@@ -0,0 +1,81 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/logr"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/gcp"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// OceanGCPClusterCostsCollector is a prometheus collector for the cost of
+// Spotinst Ocean clusters on GCP.
+type OceanGCPClusterCostsCollector struct {
+	oceanClusterCostsCollector
+}
+
+// NewOceanGCPClusterCostsCollector creates a new OceanGCPClusterCostsCollector
+// for collecting the costs of the provided list of Ocean clusters. The
+// clusterTagMappings are used to propagate the labels of the Ocean clusters
+// onto the cluster-level metrics. The remaining arguments behave like the ones
+// of NewOceanAWSClusterCostsCollector.
+func NewOceanGCPClusterCostsCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanClusterCostsClient,
+	clusters []*gcp.Cluster,
+	labelResolver labels.Resolver,
+	clusterTagMappings labels.Mappings,
+	metadata KubernetesMetadataProvider,
+	rollupWorkloads bool,
+) *OceanGCPClusterCostsCollector {
+	oceanClusters := make([]oceanCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		oceanClusters = append(oceanClusters, oceanGCPCluster(cluster))
+	}
+
+	return &OceanGCPClusterCostsCollector{
+		oceanClusterCostsCollector: newOceanClusterCostsCollector(
+			ctx,
+			logger,
+			cloudGCP,
+			client,
+			oceanClusters,
+			labelResolver,
+			clusterTagMappings,
+			metadata,
+			rollupWorkloads,
+		),
+	}
+}
+
+// oceanGCPCluster translates an Ocean cluster on GCP into its cloud provider
+// independent representation.
+func oceanGCPCluster(cluster *gcp.Cluster) oceanCluster {
+	return oceanCluster{
+		cloud:               cloudGCP,
+		id:                  spotinst.StringValue(cluster.ID),
+		name:                spotinst.StringValue(cluster.Name),
+		controllerClusterID: spotinst.StringValue(cluster.ControllerClusterID),
+		tags:                oceanGCPClusterTags(cluster),
+	}
+}
+
+// oceanGCPClusterTags returns the labels configured on the launch
+// specification of an Ocean cluster as a map. GCP labels are the equivalent
+// of AWS tags, whereas GCP network tags have no values and are ignored.
+func oceanGCPClusterTags(cluster *gcp.Cluster) map[string]string {
+	if cluster.Compute == nil || cluster.Compute.LaunchSpecification == nil {
+		return nil
+	}
+
+	clusterLabels := cluster.Compute.LaunchSpecification.Labels
+	tagMap := make(map[string]string, len(clusterLabels))
+
+	for _, label := range clusterLabels {
+		tagMap[spotinst.StringValue(label.Key)] = spotinst.StringValue(label.Value)
+	}
+
+	return tagMap
+}
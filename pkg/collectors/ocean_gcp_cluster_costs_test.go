@@ -0,0 +1,144 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bonial-International-GmbH/spotinst-metrics-exporter/pkg/labels"
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/providers/gcp"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestOceanGCPClusterCostsCollector(t *testing.T) {
+	testCases := []struct {
+		name               string
+		client             func() OceanClusterCostsClient
+		expected           string
+		labelResolver      labels.Resolver
+		clusterTagMappings labels.Mappings
+		clusters           []*gcp.Cluster
+	}{
+		{
+			name: "no cluster, no output",
+			client: func() OceanClusterCostsClient {
+				return new(mockOceanClusterCostsClient)
+			},
+		},
+		{
+			name: "nonexistent cluster",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("nonexistent")
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(nil, errors.New("nonexistent"))
+				return mockClient
+			},
+			clusters: oceanGCPClusters(nil, "nonexistent"),
+		},
+		{
+			name: "one cluster",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(
+					200,
+					namespaceCost("foo-ns", 190, resourceCost("foo-ns", "foo-deployment", 180)),
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanGCPClusters(nil, "foo"),
+			expected: `
+                # HELP spotinst_ocean_gcp_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_gcp_cluster_cost gauge
+                spotinst_ocean_gcp_cluster_cost{cloud="gcp",ocean_id="foo",ocean_name="ocean-foo"} 200
+                # HELP spotinst_ocean_gcp_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_gcp_namespace_cost gauge
+                spotinst_ocean_gcp_namespace_cost{cloud="gcp",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo"} 190
+                # HELP spotinst_ocean_gcp_workload_cost Total cost of a workload
+                # TYPE spotinst_ocean_gcp_workload_cost gauge
+                spotinst_ocean_gcp_workload_cost{cloud="gcp",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",workload="deployment"} 180
+            `,
+		},
+		{
+			name: "cluster labels and fallbacks",
+			client: func() OceanClusterCostsClient {
+				input := clusterCostInput("foo")
+				output := clusterCostOutput(
+					200,
+					namespaceCost("foo-ns", 190, resourceCostLabels("foo-ns", "foo-deployment", 180, map[string]string{
+						"team": "foo-team",
+					})),
+					namespaceCost("bar-ns", 10, resourceCost("bar-ns", "bar-deployment", 9)),
+				)
+
+				mockClient := new(mockOceanClusterCostsClient)
+				mockClient.On("GetClusterCosts", mock.Anything, input).Return(output, nil)
+				return mockClient
+			},
+			clusters: oceanGCPClusters(map[string]string{"team": "platform", "cost-center": "1234"}, "foo"),
+			labelResolver: func() labels.Resolver {
+				mappings, _ := labels.ParseMappings("team")
+				return labels.NewResolver(mappings, labels.Sources{labels.SourceCluster}, false)
+			}(),
+			clusterTagMappings: func() labels.Mappings {
+				mappings, _ := labels.ParseMappings("cost-center=cost_center")
+				return mappings
+			}(),
+			expected: `
+                # HELP spotinst_ocean_gcp_cluster_cost Total cost of an ocean cluster
+                # TYPE spotinst_ocean_gcp_cluster_cost gauge
+                spotinst_ocean_gcp_cluster_cost{cloud="gcp",cost_center="1234",ocean_id="foo",ocean_name="ocean-foo"} 200
+                # HELP spotinst_ocean_gcp_namespace_cost Total cost of a namespace
+                # TYPE spotinst_ocean_gcp_namespace_cost gauge
+                spotinst_ocean_gcp_namespace_cost{cloud="gcp",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",team="platform"} 10
+                spotinst_ocean_gcp_namespace_cost{cloud="gcp",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="platform"} 190
+                # HELP spotinst_ocean_gcp_workload_cost Total cost of a workload
+                # TYPE spotinst_ocean_gcp_workload_cost gauge
+                spotinst_ocean_gcp_workload_cost{cloud="gcp",name="bar-deployment",namespace="bar-ns",ocean_id="foo",ocean_name="ocean-foo",team="platform",workload="deployment"} 9
+                spotinst_ocean_gcp_workload_cost{cloud="gcp",name="foo-deployment",namespace="foo-ns",ocean_id="foo",ocean_name="ocean-foo",team="foo-team",workload="deployment"} 180
+            `,
+		},
+	}
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanGCPClusterCostsCollector(ctx, logger, testCase.client(), testCase.clusters, testCase.labelResolver, testCase.clusterTagMappings, nil, false)
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func oceanGCPClusters(clusterLabels map[string]string, clusterIDs ...string) []*gcp.Cluster {
+	gcpLabels := make([]*gcp.Label, 0, len(clusterLabels))
+	for key, value := range clusterLabels {
+		gcpLabels = append(gcpLabels, &gcp.Label{Key: spotinst.String(key), Value: spotinst.String(value)})
+	}
+
+	clusters := make([]*gcp.Cluster, 0, len(clusterIDs))
+
+	for _, id := range clusterIDs {
+		clusters = append(clusters, &gcp.Cluster{
+			ID:                  spotinst.String(id),
+			ControllerClusterID: spotinst.String(id),
+			Name:                spotinst.String("ocean-" + id),
+			Compute: &gcp.Compute{
+				LaunchSpecification: &gcp.LaunchSpecification{Labels: gcpLabels},
+			},
+		})
+	}
+
+	return clusters
+}
@@ -0,0 +1,125 @@
+package collectors
+
+// Values of the `cloud` label.
+const (
+	cloudAWS   = "aws"
+	cloudGCP   = "gcp"
+	cloudAzure = "azure"
+)
+
+// oceanCluster is the cloud provider independent representation of an Ocean
+// cluster. The provider specific collectors translate the clusters returned by
+// the Spotinst SDK into it, so that the costs and resource suggestions of all
+// cloud providers can be emitted by the same code.
+type oceanCluster struct {
+	cloud               string
+	id                  string
+	name                string
+	controllerClusterID string
+	tags                map[string]string
+}
+
+// labelValues returns the values of the labels returned by
+// oceanClusterLabelNames.
+func (c oceanCluster) labelValues() []string {
+	return []string{c.id, c.name, c.cloud}
+}
+
+// oceanSubsystem returns the metric subsystem of a cloud provider. Metrics
+// keep a subsystem per cloud provider, e.g. "ocean_aws", for compatibility
+// with existing dashboards.
+func oceanSubsystem(cloud string) string {
+	return "ocean_" + cloud
+}
+
+// oceanClusterLabelNames returns the names of the labels identifying an Ocean
+// cluster. A new slice is returned on every call, so callers may append to
+// it.
+func oceanClusterLabelNames() []string {
+	return []string{"ocean_id", "ocean_name", "cloud"}
+}
+
+// clusterCostRecord holds the cost of an Ocean cluster.
+type clusterCostRecord struct {
+	cost       float64
+	namespaces []namespaceCostRecord
+}
+
+// namespaceCostRecord holds the cost of a namespace and its workloads. The
+// labels are the ones reported by Spotinst.
+type namespaceCostRecord struct {
+	namespace string
+	cost      float64
+	labels    map[string]string
+	workloads []workloadCostRecord
+}
+
+// workloadCostRecord holds the cost of a workload. The labels are the ones
+// reported by Spotinst.
+type workloadCostRecord struct {
+	workload string
+	name     string
+	cost     float64
+	labels   map[string]string
+}
+
+// workloadSuggestionRecord holds the requested and suggested resources of a
+// workload and its containers.
+type workloadSuggestionRecord struct {
+	workload        string
+	namespace       string
+	name            string
+	requestedCPU    float64
+	suggestedCPU    float64
+	requestedMemory float64
+	suggestedMemory float64
+	containers      []containerSuggestionRecord
+}
+
+// key returns the key identifying the workload of the suggestion.
+func (r workloadSuggestionRecord) key() workloadKey {
+	return workloadKey{namespace: r.namespace, workload: r.workload, name: r.name}
+}
+
+// containerSuggestionRecord holds the requested and suggested resources of a
+// container.
+type containerSuggestionRecord struct {
+	name            string
+	requestedCPU    float64
+	suggestedCPU    float64
+	requestedMemory float64
+	suggestedMemory float64
+}
+
+// resolveSuggestionOwners replaces the workloads of the suggestions with
+// their owners according to the owner resolver.
+//
+// Multiple ReplicaSets or Jobs may be rolled up into the same owner. Only the
+// first suggestion is kept in this case, as summing up resource requests of
+// e.g. old and new ReplicaSets of a Deployment would be misleading.
+func resolveSuggestionOwners(
+	owners workloadOwnerResolver,
+	clusterID string,
+	suggestions []workloadSuggestionRecord,
+) []workloadSuggestionRecord {
+	seen := make(map[workloadKey]bool, len(suggestions))
+	resolved := make([]workloadSuggestionRecord, 0, len(suggestions))
+
+	for _, suggestion := range suggestions {
+		suggestion.workload, suggestion.name = owners.resolve(
+			clusterID,
+			suggestion.namespace,
+			suggestion.workload,
+			suggestion.name,
+		)
+
+		if seen[suggestion.key()] {
+			continue
+		}
+
+		seen[suggestion.key()] = true
+		resolved = append(resolved, suggestion)
+	}
+
+	return resolved
+}
@@ -0,0 +1,26 @@
+package collectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSuggestionOwners(t *testing.T) {
+	owners := workloadOwnerResolver{enabled: true, metadata: noopMetadataProvider{}}
+
+	suggestions := []workloadSuggestionRecord{
+		{workload: workloadReplicaSet, namespace: "foo-ns", name: "foo-deployment-5d8f9c7b6", requestedCPU: 100},
+		{workload: workloadReplicaSet, namespace: "foo-ns", name: "foo-deployment-7b9c5d6f8", requestedCPU: 200},
+		{workload: workloadJob, namespace: "foo-ns", name: "foo-cronjob-29345678", requestedCPU: 300},
+		{workload: workloadDeployment, namespace: "bar-ns", name: "foo-deployment", requestedCPU: 400},
+	}
+
+	expected := []workloadSuggestionRecord{
+		{workload: workloadDeployment, namespace: "foo-ns", name: "foo-deployment", requestedCPU: 100},
+		{workload: workloadCronJob, namespace: "foo-ns", name: "foo-cronjob", requestedCPU: 300},
+		{workload: workloadDeployment, namespace: "bar-ns", name: "foo-deployment", requestedCPU: 400},
+	}
+
+	assert.Equal(t, expected, resolveSuggestionOwners(owners, "foo", suggestions))
+}
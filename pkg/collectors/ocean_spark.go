@@ -0,0 +1,249 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/spark"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/spotinst/spotinst-sdk-go/spotinst/client"
+)
+
+// OceanSparkClient is the interface for listing Ocean for Apache Spark
+// clusters and their virtual node groups.
+//
+// It is implemented by the Spotinst *spark.ServiceOp client.
+type OceanSparkClient interface {
+	ListClusters(context.Context, *spark.ListClustersInput) (*spark.ListClustersOutput, error)
+	ListVirtualNodeGroups(context.Context, *spark.ListVngsInput) (*spark.ListVngsOutput, error)
+}
+
+// sparkApplication is an application of an Ocean for Apache Spark cluster as
+// returned by the applications endpoint of the Spotinst API, which is not
+// covered by the Spotinst SDK.
+type sparkApplication struct {
+	ID          string     `json:"id"`
+	DisplayName string     `json:"displayName"`
+	JobID       string     `json:"jobId"`
+	State       string     `json:"appState"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt"`
+	EndedAt     *time.Time `json:"endedAt"`
+	TotalCost   *float64   `json:"totalCost"`
+}
+
+// name returns the name the application is aggregated by. Applications of the
+// same job share the job ID, other applications are aggregated by their
+// display name without timestamps and UUIDs, in order to keep the
+// cardinality of the metrics bounded.
+func (a *sparkApplication) name() string {
+	if a.JobID != "" {
+		return a.JobID
+	}
+
+	return normalizeResourceName(a.DisplayName)
+}
+
+// duration returns the run time of a finished application.
+func (a *sparkApplication) duration() time.Duration {
+	startedAt := a.StartedAt
+	if startedAt == nil {
+		startedAt = a.CreatedAt
+	}
+
+	if startedAt == nil || a.EndedAt == nil {
+		return 0
+	}
+
+	return a.EndedAt.Sub(*startedAt)
+}
+
+// sparkApplicationRuns holds the aggregated finished runs of an application.
+type sparkApplicationRuns struct {
+	runs     float64
+	cost     float64
+	duration float64
+}
+
+// OceanSparkCollector is a prometheus collector for Ocean for Apache Spark
+// clusters, the virtual node groups dedicated to them and their applications.
+//
+// The clusters are listed on every collection. The applications are fetched
+// from the Spotinst API directly, as the Spotinst SDK does not cover them,
+// and are limited to the ones created within the lookback window.
+type OceanSparkCollector struct {
+	ctx                  context.Context
+	logger               logr.Logger
+	client               OceanSparkClient
+	apiClient            SpotinstAPIClient
+	applicationsLookback time.Duration
+	now                  func() time.Time
+	clusterInfo          *prometheus.Desc
+	virtualNodeGroup     *prometheus.Desc
+	applications         *prometheus.Desc
+	applicationRuns      *prometheus.Desc
+	applicationCost      *prometheus.Desc
+	applicationDuration  *prometheus.Desc
+}
+
+// NewOceanSparkCollector creates a new OceanSparkCollector for collecting all
+// Ocean for Apache Spark clusters of the account and their applications
+// created within applicationsLookback.
+func NewOceanSparkCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client OceanSparkClient,
+	apiClient SpotinstAPIClient,
+	applicationsLookback time.Duration,
+) *OceanSparkCollector {
+	labelNames := []string{"spark_cluster_id", "ocean_id"}
+
+	collector := &OceanSparkCollector{
+		ctx:                  ctx,
+		logger:               logger,
+		client:               client,
+		apiClient:            apiClient,
+		applicationsLookback: applicationsLookback,
+		now:                  time.Now,
+		clusterInfo: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_spark", "cluster_info"),
+			"Information about an ocean spark cluster",
+			append(labelNames, "controller_cluster_id", "region", "state"),
+			nil,
+		),
+		virtualNodeGroup: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_spark", "virtual_node_group_info"),
+			"Information about a virtual node group dedicated to an ocean spark cluster",
+			append(labelNames, "virtual_node_group_id"),
+			nil,
+		),
+		applications: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_spark", "applications"),
+			"The number of applications of an ocean spark cluster per state created within the lookback window",
+			append(labelNames, "state"),
+			nil,
+		),
+		applicationRuns: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_spark", "application_runs"),
+			"The number of finished runs of an application created within the lookback window",
+			append(labelNames, "application"),
+			nil,
+		),
+		applicationCost: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_spark", "application_cost"),
+			"The total cost of the finished runs of an application created within the lookback window",
+			append(labelNames, "application"),
+			nil,
+		),
+		applicationDuration: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "ocean_spark", "application_duration_seconds"),
+			"The total duration of the finished runs of an application created within the lookback window",
+			append(labelNames, "application"),
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *OceanSparkCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clusterInfo
+	ch <- c.virtualNodeGroup
+	ch <- c.applications
+	ch <- c.applicationRuns
+	ch <- c.applicationCost
+	ch <- c.applicationDuration
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OceanSparkCollector) Collect(ch chan<- prometheus.Metric) {
+	output, err := c.client.ListClusters(c.ctx, &spark.ListClustersInput{})
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean spark clusters")
+		return
+	}
+
+	for _, cluster := range output.Clusters {
+		clusterID := spotinst.StringValue(cluster.ID)
+		labelValues := []string{clusterID, spotinst.StringValue(cluster.OceanClusterID)}
+
+		collectGaugeValue(ch, c.clusterInfo, 1, append(
+			labelValues,
+			spotinst.StringValue(cluster.ControllerClusterID),
+			spotinst.StringValue(cluster.Region),
+			strings.ToLower(spotinst.StringValue(cluster.State)),
+		))
+
+		c.collectVirtualNodeGroups(ch, clusterID, labelValues)
+		c.collectApplications(ch, clusterID, labelValues)
+	}
+}
+
+func (c *OceanSparkCollector) collectVirtualNodeGroups(ch chan<- prometheus.Metric, clusterID string, labelValues []string) {
+	vngs, err := c.client.ListVirtualNodeGroups(c.ctx, &spark.ListVngsInput{
+		ClusterID: spotinst.String(clusterID),
+	})
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean spark virtual node groups", "spark_cluster_id", clusterID)
+		return
+	}
+
+	for _, vng := range vngs.VirtualNodeGroups {
+		collectGaugeValue(ch, c.virtualNodeGroup, 1, append(labelValues, spotinst.StringValue(vng.VngID)))
+	}
+}
+
+func (c *OceanSparkCollector) collectApplications(ch chan<- prometheus.Metric, clusterID string, labelValues []string) {
+	now := c.now().UTC()
+
+	request := client.NewRequest(http.MethodGet, fmt.Sprintf("/ocean/spark/cluster/%s/app", clusterID))
+	request.Params.Set("from", now.Add(-c.applicationsLookback).Format(time.RFC3339))
+	request.Params.Set("to", now.Format(time.RFC3339))
+
+	applications, err := listItems[sparkApplication](c.ctx, c.apiClient, request)
+	if err != nil {
+		c.logger.Error(err, "failed to list ocean spark applications", "spark_cluster_id", clusterID)
+		return
+	}
+
+	states := make(map[string]float64)
+	runs := make(map[string]*sparkApplicationRuns)
+
+	for _, application := range applications {
+		states[strings.ToLower(application.State)]++
+
+		if application.EndedAt == nil {
+			continue
+		}
+
+		name := application.name()
+
+		applicationRuns, ok := runs[name]
+		if !ok {
+			applicationRuns = &sparkApplicationRuns{}
+			runs[name] = applicationRuns
+		}
+
+		applicationRuns.runs++
+		applicationRuns.cost += spotinst.Float64Value(application.TotalCost)
+		applicationRuns.duration += application.duration().Seconds()
+	}
+
+	for state, count := range states {
+		collectGaugeValue(ch, c.applications, count, append(labelValues, state))
+	}
+
+	for name, applicationRuns := range runs {
+		applicationLabelValues := append(labelValues[:len(labelValues):len(labelValues)], name)
+
+		collectGaugeValue(ch, c.applicationRuns, applicationRuns.runs, applicationLabelValues)
+		collectGaugeValue(ch, c.applicationCost, applicationRuns.cost, applicationLabelValues)
+		collectGaugeValue(ch, c.applicationDuration, applicationRuns.duration, applicationLabelValues)
+	}
+}
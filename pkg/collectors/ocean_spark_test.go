@@ -0,0 +1,189 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spotinst/spotinst-sdk-go/service/ocean/spark"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockOceanSparkClient struct {
+	mock.Mock
+}
+
+func (m *mockOceanSparkClient) ListClusters(
+	ctx context.Context,
+	input *spark.ListClustersInput,
+) (*spark.ListClustersOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*spark.ListClustersOutput), args.Error(1)
+}
+
+func (m *mockOceanSparkClient) ListVirtualNodeGroups(
+	ctx context.Context,
+	input *spark.ListVngsInput,
+) (*spark.ListVngsOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*spark.ListVngsOutput), args.Error(1)
+}
+
+func TestOceanSparkCollector(t *testing.T) {
+	sparkClusters := &spark.ListClustersOutput{Clusters: []*spark.Cluster{
+		{
+			ID:                  spotinst.String("osc-foo"),
+			OceanClusterID:      spotinst.String("o-foo"),
+			ControllerClusterID: spotinst.String("foo"),
+			Region:              spotinst.String("eu-west-1"),
+			State:               spotinst.String("AVAILABLE"),
+		},
+		{
+			ID:             spotinst.String("osc-bar"),
+			OceanClusterID: spotinst.String("o-bar"),
+			State:          spotinst.String("PROGRESSING"),
+		},
+	}}
+
+	testCases := []struct {
+		name     string
+		client   func() OceanSparkClient
+		expected string
+	}{
+		{
+			name: "failing cluster list",
+			client: func() OceanSparkClient {
+				mockClient := new(mockOceanSparkClient)
+				mockClient.On("ListClusters", mock.Anything, &spark.ListClustersInput{}).Return(nil, errors.New("error"))
+				return mockClient
+			},
+		},
+		{
+			name: "clusters and virtual node groups",
+			client: func() OceanSparkClient {
+				vngs := &spark.ListVngsOutput{VirtualNodeGroups: []*spark.DedicatedVirtualNodeGroup{
+					{OceanClusterID: spotinst.String("o-foo"), OceanSparkClusterID: spotinst.String("osc-foo"), VngID: spotinst.String("ols-1")},
+					{OceanClusterID: spotinst.String("o-foo"), OceanSparkClusterID: spotinst.String("osc-foo"), VngID: spotinst.String("ols-2")},
+				}}
+
+				mockClient := new(mockOceanSparkClient)
+				mockClient.On("ListClusters", mock.Anything, &spark.ListClustersInput{}).Return(sparkClusters, nil)
+				mockClient.On("ListVirtualNodeGroups", mock.Anything, sparkVngsInput("osc-foo")).Return(vngs, nil)
+				mockClient.On("ListVirtualNodeGroups", mock.Anything, sparkVngsInput("osc-bar")).Return(nil, errors.New("error"))
+				return mockClient
+			},
+			expected: `
+                # HELP spotinst_ocean_spark_application_cost The total cost of the finished runs of an application created within the lookback window
+                # TYPE spotinst_ocean_spark_application_cost gauge
+                spotinst_ocean_spark_application_cost{application="nightly-etl",ocean_id="o-foo",spark_cluster_id="osc-foo"} 3.5
+                spotinst_ocean_spark_application_cost{application="spark-pi",ocean_id="o-foo",spark_cluster_id="osc-foo"} 0.25
+                # HELP spotinst_ocean_spark_application_duration_seconds The total duration of the finished runs of an application created within the lookback window
+                # TYPE spotinst_ocean_spark_application_duration_seconds gauge
+                spotinst_ocean_spark_application_duration_seconds{application="nightly-etl",ocean_id="o-foo",spark_cluster_id="osc-foo"} 5400
+                spotinst_ocean_spark_application_duration_seconds{application="spark-pi",ocean_id="o-foo",spark_cluster_id="osc-foo"} 120
+                # HELP spotinst_ocean_spark_application_runs The number of finished runs of an application created within the lookback window
+                # TYPE spotinst_ocean_spark_application_runs gauge
+                spotinst_ocean_spark_application_runs{application="nightly-etl",ocean_id="o-foo",spark_cluster_id="osc-foo"} 2
+                spotinst_ocean_spark_application_runs{application="spark-pi",ocean_id="o-foo",spark_cluster_id="osc-foo"} 1
+                # HELP spotinst_ocean_spark_applications The number of applications of an ocean spark cluster per state created within the lookback window
+                # TYPE spotinst_ocean_spark_applications gauge
+                spotinst_ocean_spark_applications{ocean_id="o-foo",spark_cluster_id="osc-foo",state="completed"} 2
+                spotinst_ocean_spark_applications{ocean_id="o-foo",spark_cluster_id="osc-foo",state="failed"} 1
+                spotinst_ocean_spark_applications{ocean_id="o-foo",spark_cluster_id="osc-foo",state="running"} 1
+                # HELP spotinst_ocean_spark_cluster_info Information about an ocean spark cluster
+                # TYPE spotinst_ocean_spark_cluster_info gauge
+                spotinst_ocean_spark_cluster_info{controller_cluster_id="",ocean_id="o-bar",region="",spark_cluster_id="osc-bar",state="progressing"} 1
+                spotinst_ocean_spark_cluster_info{controller_cluster_id="foo",ocean_id="o-foo",region="eu-west-1",spark_cluster_id="osc-foo",state="available"} 1
+                # HELP spotinst_ocean_spark_virtual_node_group_info Information about a virtual node group dedicated to an ocean spark cluster
+                # TYPE spotinst_ocean_spark_virtual_node_group_info gauge
+                spotinst_ocean_spark_virtual_node_group_info{ocean_id="o-foo",spark_cluster_id="osc-foo",virtual_node_group_id="ols-1"} 1
+                spotinst_ocean_spark_virtual_node_group_info{ocean_id="o-foo",spark_cluster_id="osc-foo",virtual_node_group_id="ols-2"} 1
+            `,
+		},
+	}
+
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	apiClient := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ocean/spark/cluster/osc-foo/app" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		assert.Equal(t, "2026-01-01T12:00:00Z", r.URL.Query().Get("from"))
+		assert.Equal(t, "2026-01-02T12:00:00Z", r.URL.Query().Get("to"))
+
+		writeAPIItems(
+			w,
+			sparkApplication{
+				ID:          "app-1",
+				DisplayName: "nightly-etl-20260101",
+				JobID:       "nightly-etl",
+				State:       "COMPLETED",
+				StartedAt:   spotinst.Time(now.Add(-10 * time.Hour)),
+				EndedAt:     spotinst.Time(now.Add(-9 * time.Hour)),
+				TotalCost:   spotinst.Float64(1.5),
+			},
+			sparkApplication{
+				ID:          "app-2",
+				DisplayName: "nightly-etl-20260102",
+				JobID:       "nightly-etl",
+				State:       "FAILED",
+				StartedAt:   spotinst.Time(now.Add(-2 * time.Hour)),
+				EndedAt:     spotinst.Time(now.Add(-90 * time.Minute)),
+				TotalCost:   spotinst.Float64(2),
+			},
+			sparkApplication{
+				ID:          "app-3",
+				DisplayName: "spark-pi-4a6f5b1e-9c3d-4e2f-8a7b-1c2d3e4f5a6b",
+				State:       "COMPLETED",
+				CreatedAt:   spotinst.Time(now.Add(-time.Hour)),
+				EndedAt:     spotinst.Time(now.Add(-58 * time.Minute)),
+				TotalCost:   spotinst.Float64(0.25),
+			},
+			sparkApplication{
+				// Still running, so it is only counted by state.
+				ID:          "app-4",
+				DisplayName: "spark-pi-0b1c2d3e-4f5a-6b7c-8d9e-0f1a2b3c4d5e",
+				State:       "RUNNING",
+				StartedAt:   spotinst.Time(now.Add(-time.Minute)),
+			},
+		)
+	})
+
+	logger := zapr.NewLogger(zap.NewNop())
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := NewOceanSparkCollector(ctx, logger, testCase.client(), apiClient, 24*time.Hour)
+			collector.now = func() time.Time { return now }
+
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(testCase.expected)))
+		})
+	}
+}
+
+func sparkVngsInput(clusterID string) *spark.ListVngsInput {
+	return &spark.ListVngsInput{ClusterID: spotinst.String(clusterID)}
+}
@@ -0,0 +1,46 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spotinst/spotinst-sdk-go/spotinst/client"
+)
+
+// SpotinstAPIClient is the interface for sending requests to endpoints of the
+// Spotinst API which are not covered by the services of the Spotinst SDK.
+//
+// It is implemented by the Spotinst *client.Client.
+type SpotinstAPIClient interface {
+	Do(context.Context, *client.Request) (*http.Response, error)
+}
+
+// listItems sends the request and decodes the items of the response, the same
+// way the services of the Spotinst SDK do.
+func listItems[T any](ctx context.Context, apiClient SpotinstAPIClient, request *client.Request) ([]*T, error) {
+	resp, err := client.RequireOK(apiClient.Do(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body client.Response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	items := make([]*T, 0, len(body.Response.Items))
+
+	for _, raw := range body.Response.Items {
+		item := new(T)
+		if err := json.Unmarshal(raw, item); err != nil {
+			return nil, fmt.Errorf("failed to decode response item: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,78 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/spotinst/spotinst-sdk-go/spotinst/client"
+	"github.com/spotinst/spotinst-sdk-go/spotinst/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPIClient creates a Spotinst API client which sends its requests to
+// handler.
+func newTestAPIClient(t *testing.T, handler http.HandlerFunc) *client.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := spotinst.DefaultConfig().
+		WithBaseURL(server.URL).
+		WithCredentials(credentials.NewStaticCredentials("token", "act-123"))
+
+	return client.New(config)
+}
+
+// writeAPIItems writes a response of the Spotinst API containing items.
+func writeAPIItems(w http.ResponseWriter, items ...any) {
+	raw := make([]json.RawMessage, len(items))
+	for i, item := range items {
+		raw[i], _ = json.Marshal(item)
+	}
+
+	var body client.Response
+	body.Response.Items = raw
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestListItems(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	apiClient := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/items":
+			assert.Equal(t, "act-123", r.URL.Query().Get("accountId"))
+			assert.Equal(t, "bar", r.URL.Query().Get("foo"))
+			writeAPIItems(w, item{Name: "foo"}, item{Name: "bar"})
+		case "/invalid":
+			_, _ = w.Write([]byte("{"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+
+	request := client.NewRequest(http.MethodGet, "/items")
+	request.Params.Set("foo", "bar")
+
+	items, err := listItems[item](ctx, apiClient, request)
+	require.NoError(t, err)
+	assert.Equal(t, []*item{{Name: "foo"}, {Name: "bar"}}, items)
+
+	_, err = listItems[item](ctx, apiClient, client.NewRequest(http.MethodGet, "/invalid"))
+	assert.Error(t, err)
+
+	_, err = listItems[item](ctx, apiClient, client.NewRequest(http.MethodGet, "/nonexistent"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,291 @@
+package collectors
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	managedinstanceaws "github.com/spotinst/spotinst-sdk-go/service/managedinstance/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+)
+
+// StatefulNodeAWSClient is the interface for listing Stateful Nodes (formerly
+// Managed Instances) and their status.
+//
+// It is implemented by the Spotinst *aws.ServiceOp client of the
+// managedinstance service.
+type StatefulNodeAWSClient interface {
+	List(
+		context.Context,
+		*managedinstanceaws.ListManagedInstancesInput,
+	) (*managedinstanceaws.ListManagedInstancesOutput, error)
+	Status(
+		context.Context,
+		*managedinstanceaws.StatusManagedInstanceInput,
+	) (*managedinstanceaws.StatusManagedInstanceOutput, error)
+}
+
+// Normalized values of the `state` label.
+const (
+	statefulNodeRunning   = "running"
+	statefulNodePaused    = "paused"
+	statefulNodeRecycling = "recycling"
+)
+
+// statefulNodeStates are the states which are always exported, so that
+// alerts can rely on explicit zeros.
+var statefulNodeStates = []string{statefulNodeRunning, statefulNodePaused, statefulNodeRecycling}
+
+// normalizeStatefulNodeState converts the status of a Stateful Node as
+// returned by the Spotinst API, e.g. "ACTIVE" or "RECYCLING", into the
+// normalized value of the `state` label.
+func normalizeStatefulNodeState(status string) string {
+	switch normalized := strings.ToLower(status); normalized {
+	case "active", "running":
+		return statefulNodeRunning
+	default:
+		return normalized
+	}
+}
+
+// statefulNodeState holds the last observed state and instance of a Stateful
+// Node and its recycle history.
+type statefulNodeState struct {
+	state       string
+	instanceID  string
+	recycles    float64
+	lastRecycle time.Time
+}
+
+// update records the state and instance observed at now. Entering the
+// recycling state is counted as a recycle, including a node which is
+// recycling when it is first observed. A changed instance is counted as a
+// recycle as well, unless the node was recycling before, so that recycles
+// which started and finished between two observations are not missed.
+func (s *statefulNodeState) update(state, instanceID string, now time.Time) {
+	switch {
+	case state == statefulNodeRecycling && s.state != statefulNodeRecycling:
+		s.recycles++
+		s.lastRecycle = now
+	case instanceID != "" && s.instanceID != "" && instanceID != s.instanceID && s.state != statefulNodeRecycling:
+		s.recycles++
+		s.lastRecycle = now
+	}
+
+	s.state = state
+
+	if instanceID != "" {
+		s.instanceID = instanceID
+	}
+}
+
+// StatefulNodeAWSCollector is a prometheus collector for the state of
+// Spotinst Stateful Nodes on AWS.
+//
+// The nodes are listed on every collection. Recycles are detected by
+// comparing the state and instance of a node with the ones observed by the
+// previous collection, so the counters only include recycles which were
+// observed after the collector was created.
+type StatefulNodeAWSCollector struct {
+	ctx         context.Context
+	logger      logr.Logger
+	client      StatefulNodeAWSClient
+	now         func() time.Time
+	mu          sync.Mutex
+	states      map[string]*statefulNodeState
+	info        *prometheus.Desc
+	state       *prometheus.Desc
+	recycles    *prometheus.Desc
+	lastRecycle *prometheus.Desc
+}
+
+// NewStatefulNodeAWSCollector creates a new StatefulNodeAWSCollector for
+// collecting the state of all Stateful Nodes of the account.
+func NewStatefulNodeAWSCollector(
+	ctx context.Context,
+	logger logr.Logger,
+	client StatefulNodeAWSClient,
+) *StatefulNodeAWSCollector {
+	labelNames := []string{"stateful_node_id", "stateful_node_name"}
+
+	collector := &StatefulNodeAWSCollector{
+		ctx:    ctx,
+		logger: logger,
+		client: client,
+		now:    time.Now,
+		states: make(map[string]*statefulNodeState),
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "stateful_node_aws", "node_info"),
+			"Information about a stateful node",
+			append(labelNames, "region", "lifecycle", "instance_type", "instance_id"),
+			nil,
+		),
+		state: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "stateful_node_aws", "node_state"),
+			"Whether a stateful node is in a state",
+			append(labelNames, "state"),
+			nil,
+		),
+		recycles: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "stateful_node_aws", "node_recycles_total"),
+			"The number of recycles of a stateful node",
+			labelNames,
+			nil,
+		),
+		lastRecycle: prometheus.NewDesc(
+			prometheus.BuildFQName("spotinst", "stateful_node_aws", "node_last_recycle_timestamp_seconds"),
+			"The time the last recycle of a stateful node was observed",
+			labelNames,
+			nil,
+		),
+	}
+
+	return collector
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *StatefulNodeAWSCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.state
+	ch <- c.recycles
+	ch <- c.lastRecycle
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *StatefulNodeAWSCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	output, err := c.client.List(c.ctx, &managedinstanceaws.ListManagedInstancesInput{})
+	if err != nil {
+		c.logger.Error(err, "failed to list stateful nodes")
+		return
+	}
+
+	statuses := fetchConcurrently(
+		output.ManagedInstances,
+		func(node *managedinstanceaws.ManagedInstance) (*managedinstanceaws.StatusManagedInstanceOutput, error) {
+			return c.client.Status(c.ctx, &managedinstanceaws.StatusManagedInstanceInput{
+				ManagedInstanceID: node.ID,
+			})
+		},
+	)
+
+	now := c.now().UTC()
+	listed := make(map[string]bool, len(output.ManagedInstances))
+
+	for i, node := range output.ManagedInstances {
+		nodeID := spotinst.StringValue(node.ID)
+		listed[nodeID] = true
+
+		state, ok := c.states[nodeID]
+		if !ok {
+			state = &statefulNodeState{}
+			c.states[nodeID] = state
+		}
+
+		status := statuses[i]
+		if status.err != nil {
+			// The counters collected so far are still exported in order to
+			// avoid gaps.
+			c.logger.Error(status.err, "failed to fetch stateful node status", "stateful_node_id", nodeID)
+			c.collectRecycles(ch, state, node)
+			continue
+		}
+
+		var instance *managedinstanceaws.StatusManagedInstances
+		if len(status.value.StatusManagedInstance) > 0 {
+			instance = status.value.StatusManagedInstance[0]
+		}
+
+		c.collectNode(ch, state, node, instance, now)
+	}
+
+	// Forget deleted nodes.
+	for nodeID := range c.states {
+		if !listed[nodeID] {
+			delete(c.states, nodeID)
+		}
+	}
+}
+
+func (c *StatefulNodeAWSCollector) collectNode(
+	ch chan<- prometheus.Metric,
+	state *statefulNodeState,
+	node *managedinstanceaws.ManagedInstance,
+	instance *managedinstanceaws.StatusManagedInstances,
+	now time.Time,
+) {
+	labelValues := []string{spotinst.StringValue(node.ID), spotinst.StringValue(node.Name)}
+
+	var lifecycle string
+	if node.Strategy != nil {
+		lifecycle = normalizeLifecycle(spotinst.StringValue(node.Strategy.LifeCycle))
+	}
+
+	var instanceType, instanceID string
+
+	if instance != nil {
+		instanceType = spotinst.StringValue(instance.InstanceType)
+		instanceID = spotinst.StringValue(instance.InstanceID)
+
+		state.update(normalizeStatefulNodeState(spotinst.StringValue(instance.Status)), instanceID, now)
+
+		// The lifecycle of the running instance takes precedence over the
+		// configured one, e.g. after a fallback to on-demand.
+		if instance.LifeCycle != nil {
+			lifecycle = normalizeLifecycle(*instance.LifeCycle)
+		}
+	}
+
+	collectGaugeValue(ch, c.info, 1, append(
+		labelValues,
+		spotinst.StringValue(node.Region),
+		lifecycle,
+		instanceType,
+		instanceID,
+	))
+
+	if state.state != "" {
+		c.collectState(ch, state.state, labelValues)
+	}
+
+	c.collectRecycles(ch, state, node)
+}
+
+// collectState collects the known states of a node, including the current
+// state if it is not one of them.
+func (c *StatefulNodeAWSCollector) collectState(ch chan<- prometheus.Metric, current string, labelValues []string) {
+	known := false
+
+	for _, state := range statefulNodeStates {
+		value := 0.0
+		if state == current {
+			value = 1
+			known = true
+		}
+
+		collectGaugeValue(ch, c.state, value, append(labelValues, state))
+	}
+
+	if !known {
+		collectGaugeValue(ch, c.state, 1, append(labelValues, current))
+	}
+}
+
+func (c *StatefulNodeAWSCollector) collectRecycles(
+	ch chan<- prometheus.Metric,
+	state *statefulNodeState,
+	node *managedinstanceaws.ManagedInstance,
+) {
+	labelValues := []string{spotinst.StringValue(node.ID), spotinst.StringValue(node.Name)}
+
+	collectCounterValue(ch, c.recycles, state.recycles, labelValues)
+
+	if !state.lastRecycle.IsZero() {
+		collectGaugeValue(ch, c.lastRecycle, float64(state.lastRecycle.Unix()), labelValues)
+	}
+}
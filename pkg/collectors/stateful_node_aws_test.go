@@ -0,0 +1,190 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	managedinstanceaws "github.com/spotinst/spotinst-sdk-go/service/managedinstance/providers/aws"
+	"github.com/spotinst/spotinst-sdk-go/spotinst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockStatefulNodeAWSClient struct {
+	mock.Mock
+}
+
+func (m *mockStatefulNodeAWSClient) List(
+	ctx context.Context,
+	input *managedinstanceaws.ListManagedInstancesInput,
+) (*managedinstanceaws.ListManagedInstancesOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*managedinstanceaws.ListManagedInstancesOutput), args.Error(1)
+}
+
+func (m *mockStatefulNodeAWSClient) Status(
+	ctx context.Context,
+	input *managedinstanceaws.StatusManagedInstanceInput,
+) (*managedinstanceaws.StatusManagedInstanceOutput, error) {
+	args := m.Called(ctx, input)
+	output := args.Get(0)
+
+	if output == nil {
+		return nil, args.Error(1)
+	}
+
+	return output.(*managedinstanceaws.StatusManagedInstanceOutput), args.Error(1)
+}
+
+func TestStatefulNodeAWSCollector(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	nodes := &managedinstanceaws.ListManagedInstancesOutput{ManagedInstances: []*managedinstanceaws.ManagedInstance{
+		{
+			ID:       spotinst.String("smi-foo"),
+			Name:     spotinst.String("foo"),
+			Region:   spotinst.String("eu-west-1"),
+			Strategy: &managedinstanceaws.Strategy{LifeCycle: spotinst.String("spot")},
+		},
+	}}
+
+	mockClient := new(mockStatefulNodeAWSClient)
+	mockClient.On("List", mock.Anything, &managedinstanceaws.ListManagedInstancesInput{}).Return(nodes, nil)
+	mockClient.On("Status", mock.Anything, statefulNodeStatusInput("smi-foo")).
+		Return(statefulNodeStatus("ACTIVE", "i-1", "SPOT"), nil).Once()
+	mockClient.On("Status", mock.Anything, statefulNodeStatusInput("smi-foo")).
+		Return(statefulNodeStatus("RECYCLING", "i-1", "SPOT"), nil).Times(2)
+	mockClient.On("Status", mock.Anything, statefulNodeStatusInput("smi-foo")).
+		Return(nil, errors.New("error")).Once()
+
+	logger := zapr.NewLogger(zap.NewNop())
+	collector := NewStatefulNodeAWSCollector(context.Background(), logger, mockClient)
+
+	now := start
+	collector.now = func() time.Time { return now }
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+        # HELP spotinst_stateful_node_aws_node_info Information about a stateful node
+        # TYPE spotinst_stateful_node_aws_node_info gauge
+        spotinst_stateful_node_aws_node_info{instance_id="i-1",instance_type="m5.large",lifecycle="spot",region="eu-west-1",stateful_node_id="smi-foo",stateful_node_name="foo"} 1
+        # HELP spotinst_stateful_node_aws_node_recycles_total The number of recycles of a stateful node
+        # TYPE spotinst_stateful_node_aws_node_recycles_total counter
+        spotinst_stateful_node_aws_node_recycles_total{stateful_node_id="smi-foo",stateful_node_name="foo"} 0
+        # HELP spotinst_stateful_node_aws_node_state Whether a stateful node is in a state
+        # TYPE spotinst_stateful_node_aws_node_state gauge
+        spotinst_stateful_node_aws_node_state{state="paused",stateful_node_id="smi-foo",stateful_node_name="foo"} 0
+        spotinst_stateful_node_aws_node_state{state="recycling",stateful_node_id="smi-foo",stateful_node_name="foo"} 0
+        spotinst_stateful_node_aws_node_state{state="running",stateful_node_id="smi-foo",stateful_node_name="foo"} 1
+    `)))
+
+	expected := `
+        # HELP spotinst_stateful_node_aws_node_info Information about a stateful node
+        # TYPE spotinst_stateful_node_aws_node_info gauge
+        spotinst_stateful_node_aws_node_info{instance_id="i-1",instance_type="m5.large",lifecycle="spot",region="eu-west-1",stateful_node_id="smi-foo",stateful_node_name="foo"} 1
+        # HELP spotinst_stateful_node_aws_node_last_recycle_timestamp_seconds The time the last recycle of a stateful node was observed
+        # TYPE spotinst_stateful_node_aws_node_last_recycle_timestamp_seconds gauge
+        spotinst_stateful_node_aws_node_last_recycle_timestamp_seconds{stateful_node_id="smi-foo",stateful_node_name="foo"} 1.76722566e+09
+        # HELP spotinst_stateful_node_aws_node_recycles_total The number of recycles of a stateful node
+        # TYPE spotinst_stateful_node_aws_node_recycles_total counter
+        spotinst_stateful_node_aws_node_recycles_total{stateful_node_id="smi-foo",stateful_node_name="foo"} 1
+        # HELP spotinst_stateful_node_aws_node_state Whether a stateful node is in a state
+        # TYPE spotinst_stateful_node_aws_node_state gauge
+        spotinst_stateful_node_aws_node_state{state="paused",stateful_node_id="smi-foo",stateful_node_name="foo"} 0
+        spotinst_stateful_node_aws_node_state{state="recycling",stateful_node_id="smi-foo",stateful_node_name="foo"} 1
+        spotinst_stateful_node_aws_node_state{state="running",stateful_node_id="smi-foo",stateful_node_name="foo"} 0
+    `
+
+	now = start.Add(time.Minute)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// A node which is still recycling is not counted again.
+	now = start.Add(2 * time.Minute)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// The recycle counters are still exported if fetching the status fails.
+	now = start.Add(3 * time.Minute)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+        # HELP spotinst_stateful_node_aws_node_last_recycle_timestamp_seconds The time the last recycle of a stateful node was observed
+        # TYPE spotinst_stateful_node_aws_node_last_recycle_timestamp_seconds gauge
+        spotinst_stateful_node_aws_node_last_recycle_timestamp_seconds{stateful_node_id="smi-foo",stateful_node_name="foo"} 1.76722566e+09
+        # HELP spotinst_stateful_node_aws_node_recycles_total The number of recycles of a stateful node
+        # TYPE spotinst_stateful_node_aws_node_recycles_total counter
+        spotinst_stateful_node_aws_node_recycles_total{stateful_node_id="smi-foo",stateful_node_name="foo"} 1
+    `)))
+}
+
+func TestStatefulNodeState(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	state := &statefulNodeState{}
+
+	state.update(statefulNodeRunning, "i-1", start)
+	assert.Equal(t, float64(0), state.recycles)
+
+	// A recycle which started and finished between two observations.
+	state.update(statefulNodeRunning, "i-2", start.Add(time.Minute))
+	assert.Equal(t, float64(1), state.recycles)
+	assert.Equal(t, start.Add(time.Minute), state.lastRecycle)
+
+	state.update(statefulNodeRecycling, "i-2", start.Add(2*time.Minute))
+	assert.Equal(t, float64(2), state.recycles)
+
+	// The new instance of an observed recycle is not counted again.
+	state.update(statefulNodeRunning, "i-3", start.Add(3*time.Minute))
+	assert.Equal(t, float64(2), state.recycles)
+
+	// A missing instance is not a recycle.
+	state.update(statefulNodePaused, "", start.Add(4*time.Minute))
+	state.update(statefulNodeRunning, "i-3", start.Add(5*time.Minute))
+	assert.Equal(t, float64(2), state.recycles)
+	assert.Equal(t, start.Add(2*time.Minute), state.lastRecycle)
+}
+
+func TestStatefulNodeAWSCollectorListError(t *testing.T) {
+	mockClient := new(mockStatefulNodeAWSClient)
+	mockClient.On("List", mock.Anything, &managedinstanceaws.ListManagedInstancesInput{}).Return(nil, errors.New("error"))
+
+	logger := zapr.NewLogger(zap.NewNop())
+	collector := NewStatefulNodeAWSCollector(context.Background(), logger, mockClient)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader("")))
+}
+
+func TestNormalizeStatefulNodeState(t *testing.T) {
+	assert.Equal(t, "running", normalizeStatefulNodeState("ACTIVE"))
+	assert.Equal(t, "paused", normalizeStatefulNodeState("PAUSED"))
+	assert.Equal(t, "recycling", normalizeStatefulNodeState("RECYCLING"))
+	assert.Equal(t, "resuming", normalizeStatefulNodeState("RESUMING"))
+}
+
+func statefulNodeStatusInput(nodeID string) *managedinstanceaws.StatusManagedInstanceInput {
+	return &managedinstanceaws.StatusManagedInstanceInput{ManagedInstanceID: spotinst.String(nodeID)}
+}
+
+func statefulNodeStatus(status, instanceID, lifecycle string) *managedinstanceaws.StatusManagedInstanceOutput {
+	return &managedinstanceaws.StatusManagedInstanceOutput{
+		StatusManagedInstance: []*managedinstanceaws.StatusManagedInstances{
+			{
+				Status:       spotinst.String(status),
+				InstanceID:   spotinst.String(instanceID),
+				InstanceType: spotinst.String("m5.large"),
+				LifeCycle:    spotinst.String(lifecycle),
+			},
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package collectors
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Normalized values of the `workload` label.
+const (
+	workloadDeployment  = "deployment"
+	workloadDaemonSet   = "daemonset"
+	workloadStatefulSet = "statefulset"
+	workloadReplicaSet  = "replicaset"
+	workloadJob         = "job"
+	workloadCronJob     = "cronjob"
+)
+
+// normalizeWorkload converts a workload kind as returned by the different
+// Spotinst APIs, e.g. "DaemonSet" or "daemon_set", into the normalized value
+// of the `workload` label.
+func normalizeWorkload(kind string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(kind))
+}
+
+var (
+	// Matches the scheduled time suffix which CronJobs append to the names
+	// of the Jobs they create.
+	cronJobSuffixRegex = regexp.MustCompile(`^(.+)-[0-9]{8,}$`)
+
+	// Matches the pod template hash suffix which Deployments append to the
+	// names of the ReplicaSets they create.
+	replicaSetSuffixRegex = regexp.MustCompile(`^(.+)-[bcdfghjklmnpqrstvwxz2456789]{5,10}$`)
+)
+
+// workloadOwnerResolver resolves the owning workload of Jobs and ReplicaSets.
+type workloadOwnerResolver struct {
+	enabled  bool
+	metadata KubernetesMetadataProvider
+}
+
+// resolve returns the workload and name a workload should be rolled up into.
+// The owner is looked up via the owner references provided by the Kubernetes
+// metadata, falling back to inferring it from the workload name. If roll-up
+// is disabled or no owner can be determined, workload and name are returned
+// unchanged.
+func (r workloadOwnerResolver) resolve(clusterID, namespace, workload, name string) (string, string) {
+	if !r.enabled {
+		return workload, name
+	}
+
+	var ownerWorkload string
+	var suffixRegex *regexp.Regexp
+
+	switch workload {
+	case workloadJob:
+		ownerWorkload, suffixRegex = workloadCronJob, cronJobSuffixRegex
+	case workloadReplicaSet:
+		ownerWorkload, suffixRegex = workloadDeployment, replicaSetSuffixRegex
+	default:
+		return workload, name
+	}
+
+	if owner, ownerName := r.metadata.WorkloadOwner(clusterID, namespace, workload, name); owner != "" {
+		if owner == ownerWorkload {
+			return owner, ownerName
+		}
+
+		// The workload is owned by something else, e.g. a Job created by
+		// a custom controller.
+		return workload, name
+	}
+
+	if matches := suffixRegex.FindStringSubmatch(name); matches != nil {
+		return ownerWorkload, matches[1]
+	}
+
+	return workload, name
+}
+
+// workloadKey identifies a workload within a cluster.
+type workloadKey struct {
+	namespace string
+	workload  string
+	name      string
+}
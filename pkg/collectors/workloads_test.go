@@ -0,0 +1,59 @@
+package collectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeWorkload(t *testing.T) {
+	for input, expected := range map[string]string{
+		"deployment":   workloadDeployment,
+		"Deployment":   workloadDeployment,
+		"DaemonSet":    workloadDaemonSet,
+		"daemon_set":   workloadDaemonSet,
+		"Stateful-Set": workloadStatefulSet,
+		"CronJob":      workloadCronJob,
+	} {
+		assert.Equal(t, expected, normalizeWorkload(input), input)
+	}
+}
+
+func TestWorkloadOwnerResolver(t *testing.T) {
+	metadata := fakeMetadataProvider{
+		owners: map[string]string{
+			"foo/foo-ns/job/foo-job":           "cronjob/foo-cronjob",
+			"foo/foo-ns/job/bar-job-29345678":  "workflow/bar",
+			"foo/foo-ns/replicaset/foo-rs-abc": "deployment/foo-deployment",
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		enabled          bool
+		workload         string
+		workloadName     string
+		expectedWorkload string
+		expectedName     string
+	}{
+		{"disabled", false, workloadJob, "foo-job", workloadJob, "foo-job"},
+		{"owner reference", true, workloadJob, "foo-job", workloadCronJob, "foo-cronjob"},
+		{"foreign owner reference", true, workloadJob, "bar-job-29345678", workloadJob, "bar-job-29345678"},
+		{"job name pattern", true, workloadJob, "baz-cronjob-29345678", workloadCronJob, "baz-cronjob"},
+		{"job without owner", true, workloadJob, "baz-job", workloadJob, "baz-job"},
+		{"replicaset owner reference", true, workloadReplicaSet, "foo-rs-abc", workloadDeployment, "foo-deployment"},
+		{"replicaset name pattern", true, workloadReplicaSet, "bar-deployment-5d8f9c7b6", workloadDeployment, "bar-deployment"},
+		{"replicaset without owner", true, workloadReplicaSet, "bar-worker", workloadReplicaSet, "bar-worker"},
+		{"other workloads", true, workloadDeployment, "foo-deployment-5d8f9c7b6", workloadDeployment, "foo-deployment-5d8f9c7b6"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resolver := workloadOwnerResolver{enabled: testCase.enabled, metadata: metadata}
+
+			workload, name := resolver.resolve("foo", "foo-ns", testCase.workload, testCase.workloadName)
+			assert.Equal(t, testCase.expectedWorkload, workload)
+			assert.Equal(t, testCase.expectedName, name)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	controllerConfigMapNamespace = "kube-system"
+	controllerConfigMapName      = "spotinst-kubernetes-cluster-controller-config"
+	controllerClusterIDKey       = "spotinst.cluster-identifier"
+)
+
+// LoadConfig loads the Kubernetes client config from the provided kubeconfig
+// path. If kubeconfig is empty, the default loading rules are used which
+// consider $KUBECONFIG and ~/.kube/config, falling back to the in-cluster
+// config.
+//
+// Returns an error if no usable config was found.
+func LoadConfig(kubeconfig string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+// DiscoverClusterID looks up the Ocean controller cluster ID from the
+// configuration of the Ocean controller running in the cluster.
+func DiscoverClusterID(ctx context.Context, client kubernetes.Interface) (string, error) {
+	configMap, err := client.CoreV1().ConfigMaps(controllerConfigMapNamespace).Get(ctx, controllerConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	clusterID := configMap.Data[controllerClusterIDKey]
+	if clusterID == "" {
+		return "", fmt.Errorf("configmap %s/%s does not contain %q", controllerConfigMapNamespace, controllerConfigMapName, controllerClusterIDKey)
+	}
+
+	return clusterID, nil
+}
@@ -0,0 +1,46 @@
+package enrichment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDiscoverClusterID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("configmap exists", func(t *testing.T) {
+		client := fake.NewClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controllerConfigMapName,
+				Namespace: controllerConfigMapNamespace,
+			},
+			Data: map[string]string{controllerClusterIDKey: "foo-cluster"},
+		})
+
+		clusterID, err := DiscoverClusterID(ctx, client)
+		assert.NoError(t, err)
+		assert.Equal(t, "foo-cluster", clusterID)
+	})
+
+	t.Run("configmap without cluster ID", func(t *testing.T) {
+		client := fake.NewClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controllerConfigMapName,
+				Namespace: controllerConfigMapNamespace,
+			},
+		})
+
+		_, err := DiscoverClusterID(ctx, client)
+		assert.Error(t, err)
+	})
+
+	t.Run("configmap missing", func(t *testing.T) {
+		_, err := DiscoverClusterID(ctx, fake.NewClientset())
+		assert.Error(t, err)
+	})
+}
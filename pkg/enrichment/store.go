@@ -0,0 +1,220 @@
+// Package enrichment provides Kubernetes metadata for namespaces and workloads
+// which is used to enrich Spotinst metrics.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+)
+
+var errCacheSyncFailed = errors.New("failed to sync informer caches")
+
+// Store is an informer backed cache of the labels and annotations of
+// namespaces and workloads of a single Kubernetes cluster.
+type Store struct {
+	clusterID string
+	factory   informers.SharedInformerFactory
+	listers   map[string]func(namespace, name string) (metav1.Object, error)
+}
+
+// NewStore creates a new Store which watches the namespaces and workloads of
+// the Kubernetes cluster behind client. ClusterID is the Ocean controller
+// cluster ID of that cluster and is used to only enrich metrics belonging to
+// it.
+func NewStore(client kubernetes.Interface, clusterID string, resyncPeriod time.Duration) *Store {
+	factory := informers.NewSharedInformerFactory(client, resyncPeriod)
+
+	namespaces := factory.Core().V1().Namespaces().Lister()
+	deployments := factory.Apps().V1().Deployments().Lister()
+	daemonSets := factory.Apps().V1().DaemonSets().Lister()
+	statefulSets := factory.Apps().V1().StatefulSets().Lister()
+	replicaSets := factory.Apps().V1().ReplicaSets().Lister()
+	jobs := factory.Batch().V1().Jobs().Lister()
+	cronJobs := factory.Batch().V1().CronJobs().Lister()
+
+	return &Store{
+		clusterID: clusterID,
+		factory:   factory,
+		listers: map[string]func(namespace, name string) (metav1.Object, error){
+			"namespace": func(_, name string) (metav1.Object, error) {
+				return namespaces.Get(name)
+			},
+			"deployment": func(namespace, name string) (metav1.Object, error) {
+				return deployments.Deployments(namespace).Get(name)
+			},
+			"daemonset": func(namespace, name string) (metav1.Object, error) {
+				return daemonSets.DaemonSets(namespace).Get(name)
+			},
+			"statefulset": func(namespace, name string) (metav1.Object, error) {
+				return statefulSets.StatefulSets(namespace).Get(name)
+			},
+			"replicaset": func(namespace, name string) (metav1.Object, error) {
+				return replicaSets.ReplicaSets(namespace).Get(name)
+			},
+			"job": func(namespace, name string) (metav1.Object, error) {
+				return jobs.Jobs(namespace).Get(name)
+			},
+			"cronjob": func(namespace, name string) (metav1.Object, error) {
+				return cronJobs.CronJobs(namespace).Get(name)
+			},
+		},
+	}
+}
+
+// Start starts the informers and blocks until their caches are synced. Returns
+// an error if the caches are not synced within syncTimeout, e.g. because the
+// exporter is not allowed to list some of the resources. The informers are
+// stopped in that case, otherwise they run until ctx is cancelled.
+func (s *Store) Start(ctx context.Context, syncTimeout time.Duration) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		if err != nil {
+			cancel()
+			s.factory.Shutdown()
+		}
+	}()
+
+	s.factory.Start(ctx.Done())
+
+	syncCtx, cancelSync := context.WithTimeout(ctx, syncTimeout)
+	defer cancelSync()
+
+	for informerType, synced := range s.factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			return fmt.Errorf("%w: %s", errCacheSyncFailed, informerType)
+		}
+	}
+
+	return nil
+}
+
+// NamespaceMetadata returns the labels and annotations of a namespace merged
+// into a single map. Labels take precedence over annotations with the same
+// key. Returns nil if the namespace is unknown or belongs to a different
+// cluster.
+func (s *Store) NamespaceMetadata(clusterID, namespace string) map[string]string {
+	return s.metadata(clusterID, "namespace", "", namespace)
+}
+
+// WorkloadMetadata returns the labels and annotations of a workload merged
+// into a single map. Labels take precedence over annotations with the same
+// key. The workload is the lowercase kind of the workload, e.g.
+// "deployment". Returns nil if the workload is unknown or belongs to a
+// different cluster.
+func (s *Store) WorkloadMetadata(clusterID, namespace, workload, name string) map[string]string {
+	return s.metadata(clusterID, workload, namespace, name)
+}
+
+// WorkloadOwner returns the lowercase kind and the name of the controller
+// owning a workload, e.g. the CronJob which created a Job. Returns empty
+// strings if the workload is unknown, has no controller or belongs to a
+// different cluster.
+func (s *Store) WorkloadOwner(clusterID, namespace, workload, name string) (string, string) {
+	obj := s.get(clusterID, workload, namespace, name)
+	if obj == nil {
+		return "", ""
+	}
+
+	owner := metav1.GetControllerOfNoCopy(obj)
+	if owner == nil {
+		return "", ""
+	}
+
+	return strings.ToLower(owner.Kind), owner.Name
+}
+
+// ContainerLimits returns the CPU limit in milli-CPU and the memory limit in
+// MiB of a container in the pod template of a workload. Limits which are not
+// set are returned as zero, as well as the limits of unknown containers or
+// workloads belonging to a different cluster.
+func (s *Store) ContainerLimits(clusterID, namespace, workload, name, container string) (float64, float64) {
+	spec := podSpec(s.get(clusterID, workload, namespace, name))
+	if spec == nil {
+		return 0, 0
+	}
+
+	for _, c := range spec.Containers {
+		if c.Name != container {
+			continue
+		}
+
+		limits := c.Resources.Limits
+
+		return float64(limits.Cpu().MilliValue()), float64(limits.Memory().Value()) / (1 << 20)
+	}
+
+	return 0, 0
+}
+
+func (s *Store) metadata(clusterID, kind, namespace, name string) map[string]string {
+	obj := s.get(clusterID, kind, namespace, name)
+	if obj == nil {
+		return nil
+	}
+
+	return mergeMetadata(obj)
+}
+
+func (s *Store) get(clusterID, kind, namespace, name string) metav1.Object {
+	if clusterID != s.clusterID {
+		return nil
+	}
+
+	lister, ok := s.listers[kind]
+	if !ok {
+		return nil
+	}
+
+	obj, err := lister(namespace, name)
+	if err != nil {
+		return nil
+	}
+
+	return obj
+}
+
+// podSpec returns the pod template spec of a workload, or nil if obj is not a
+// workload.
+func podSpec(obj metav1.Object) *corev1.PodSpec {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		return &workload.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Template.Spec
+	case *appsv1.ReplicaSet:
+		return &workload.Spec.Template.Spec
+	case *batchv1.Job:
+		return &workload.Spec.Template.Spec
+	case *batchv1.CronJob:
+		return &workload.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil
+	}
+}
+
+func mergeMetadata(obj metav1.Object) map[string]string {
+	labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+	metadata := make(map[string]string, len(labels)+len(annotations))
+
+	for key, value := range annotations {
+		metadata[key] = value
+	}
+
+	for key, value := range labels {
+		metadata[key] = value
+	}
+
+	return metadata
+}
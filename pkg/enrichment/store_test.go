@@ -0,0 +1,173 @@
+package enrichment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestStore(t *testing.T) {
+	client := fake.NewClientset(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo-ns",
+				Labels:      map[string]string{"team": "foo-team"},
+				Annotations: map[string]string{"cost-center": "1234", "team": "annotated-team"},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo-deployment",
+				Namespace:   "foo-ns",
+				Labels:      map[string]string{"app.kubernetes.io/name": "foo"},
+				Annotations: map[string]string{"owner": "alice"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "foo-container",
+								Resources: corev1.ResourceRequirements{
+									Limits: corev1.ResourceList{
+										corev1.ResourceCPU:    resource.MustParse("500m"),
+										corev1.ResourceMemory: resource.MustParse("256Mi"),
+									},
+								},
+							},
+							{Name: "sidecar"},
+						},
+					},
+				},
+			},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-deployment-5d8f9c7b6",
+				Namespace: "foo-ns",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo-deployment", Controller: ptr(true)},
+				},
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-cronjob-29345678",
+				Namespace: "foo-ns",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "batch/v1", Kind: "CronJob", Name: "foo-cronjob", Controller: ptr(true)},
+				},
+			},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-cronjob",
+				Namespace: "foo-ns",
+				Labels:    map[string]string{"team": "cron-team"},
+			},
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewStore(client, "foo-cluster", 0)
+	require.NoError(t, store.Start(ctx, time.Minute))
+
+	t.Run("namespace", func(t *testing.T) {
+		assert.Equal(
+			t,
+			map[string]string{"team": "foo-team", "cost-center": "1234"},
+			store.NamespaceMetadata("foo-cluster", "foo-ns"),
+		)
+		assert.Nil(t, store.NamespaceMetadata("foo-cluster", "nonexistent"))
+	})
+
+	t.Run("workloads", func(t *testing.T) {
+		assert.Equal(
+			t,
+			map[string]string{"app.kubernetes.io/name": "foo", "owner": "alice"},
+			store.WorkloadMetadata("foo-cluster", "foo-ns", "deployment", "foo-deployment"),
+		)
+		assert.Equal(
+			t,
+			map[string]string{"team": "cron-team"},
+			store.WorkloadMetadata("foo-cluster", "foo-ns", "cronjob", "foo-cronjob"),
+		)
+		assert.Nil(t, store.WorkloadMetadata("foo-cluster", "foo-ns", "statefulset", "foo-deployment"))
+		assert.Nil(t, store.WorkloadMetadata("foo-cluster", "foo-ns", "unknown", "foo-deployment"))
+	})
+
+	t.Run("owners", func(t *testing.T) {
+		workload, name := store.WorkloadOwner("foo-cluster", "foo-ns", "job", "foo-cronjob-29345678")
+		assert.Equal(t, "cronjob", workload)
+		assert.Equal(t, "foo-cronjob", name)
+
+		workload, name = store.WorkloadOwner("foo-cluster", "foo-ns", "replicaset", "foo-deployment-5d8f9c7b6")
+		assert.Equal(t, "deployment", workload)
+		assert.Equal(t, "foo-deployment", name)
+
+		workload, name = store.WorkloadOwner("foo-cluster", "foo-ns", "deployment", "foo-deployment")
+		assert.Empty(t, workload)
+		assert.Empty(t, name)
+	})
+
+	t.Run("container limits", func(t *testing.T) {
+		cpu, memory := store.ContainerLimits("foo-cluster", "foo-ns", "deployment", "foo-deployment", "foo-container")
+		assert.Equal(t, 500.0, cpu)
+		assert.Equal(t, 256.0, memory)
+
+		cpu, memory = store.ContainerLimits("foo-cluster", "foo-ns", "deployment", "foo-deployment", "sidecar")
+		assert.Zero(t, cpu)
+		assert.Zero(t, memory)
+
+		cpu, memory = store.ContainerLimits("foo-cluster", "foo-ns", "deployment", "foo-deployment", "nonexistent")
+		assert.Zero(t, cpu)
+		assert.Zero(t, memory)
+
+		cpu, memory = store.ContainerLimits("foo-cluster", "foo-ns", "namespace", "foo-ns", "foo-container")
+		assert.Zero(t, cpu)
+		assert.Zero(t, memory)
+	})
+
+	t.Run("other cluster", func(t *testing.T) {
+		assert.Nil(t, store.NamespaceMetadata("other-cluster", "foo-ns"))
+		assert.Nil(t, store.WorkloadMetadata("other-cluster", "foo-ns", "deployment", "foo-deployment"))
+
+		workload, _ := store.WorkloadOwner("other-cluster", "foo-ns", "job", "foo-cronjob-29345678")
+		assert.Empty(t, workload)
+
+		cpu, _ := store.ContainerLimits("other-cluster", "foo-ns", "deployment", "foo-deployment", "foo-container")
+		assert.Zero(t, cpu)
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestStoreSyncTimeout(t *testing.T) {
+	client := fake.NewClientset()
+	client.PrependReactor("list", "cronjobs", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "batch", Resource: "cronjobs"}, "", nil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewStore(client, "foo-cluster", 0)
+	assert.ErrorIs(t, store.Start(ctx, 100*time.Millisecond), errCacheSyncFailed)
+}
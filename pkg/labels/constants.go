@@ -0,0 +1,84 @@
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ConstLabels is a set of static Prometheus labels which are attached to
+// every metric.
+type ConstLabels map[string]string
+
+// ParseConstLabels parses constant labels from a comma-separated list of
+// `name=value` pairs.
+//
+// Returns an error if the input is malformed or contains invalid label names.
+func ParseConstLabels(input string) (ConstLabels, error) {
+	pairs := strings.Split(input, ",")
+	constLabels := make(ConstLabels, len(pairs))
+
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("constant label %q must have the form name=value", pair)
+		}
+
+		if !labelNameRegex.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid label name %q", parts[0])
+		}
+
+		constLabels[parts[0]] = parts[1]
+	}
+
+	return constLabels, nil
+}
+
+// Set implements pflag.Value.
+func (c *ConstLabels) Set(value string) error {
+	constLabels, err := ParseConstLabels(value)
+	if err != nil {
+		return err
+	}
+
+	if *c == nil {
+		*c = make(ConstLabels, len(constLabels))
+	}
+
+	for name, value := range constLabels {
+		(*c)[name] = value
+	}
+
+	return nil
+}
+
+// String implements pflag.Value.
+func (c ConstLabels) String() string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteRune(',')
+		}
+		sb.WriteString(name)
+		sb.WriteRune('=')
+		sb.WriteString(c[name])
+	}
+
+	return sb.String()
+}
+
+// Type implements pflag.Value.
+func (c ConstLabels) Type() string {
+	return "name=value"
+}
@@ -0,0 +1,31 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstLabels(t *testing.T) {
+	t.Run("valid input", func(t *testing.T) {
+		constLabels, err := ParseConstLabels("environment=prod,region=eu-west-1,empty=")
+		assert.NoError(t, err)
+		assert.Equal(t, ConstLabels{"environment": "prod", "region": "eu-west-1", "empty": ""}, constLabels)
+		assert.Equal(t, "empty=,environment=prod,region=eu-west-1", constLabels.String())
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, input := range []string{"", "environment", "=prod", "app.kubernetes.io/name=foo", "1st=foo"} {
+			_, err := ParseConstLabels(input)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		var constLabels ConstLabels
+
+		assert.NoError(t, constLabels.Set("environment=dev"))
+		assert.NoError(t, constLabels.Set("environment=prod,region=eu-west-1"))
+		assert.Equal(t, ConstLabels{"environment": "prod", "region": "eu-west-1"}, constLabels)
+	})
+}
@@ -0,0 +1,158 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source identifies where the value of a mapped label was resolved from.
+type Source string
+
+const (
+	// SourceResource refers to the labels of a workload resource, e.g. a
+	// Deployment.
+	SourceResource Source = "resource"
+	// SourceNamespace refers to the labels of a Kubernetes namespace.
+	SourceNamespace Source = "namespace"
+	// SourceCluster refers to the tags of an Ocean cluster.
+	SourceCluster Source = "cluster"
+)
+
+// sourceSpecificity orders sources from the most to the least specific one.
+// Label values are only ever inherited from a less specific source.
+var sourceSpecificity = map[Source]int{
+	SourceResource:  0,
+	SourceNamespace: 1,
+	SourceCluster:   2,
+}
+
+// Sources is a list of label sources.
+type Sources []Source
+
+// ParseSources parses a comma-separated list of label sources.
+//
+// Returns an error if the input contains unknown sources.
+func ParseSources(input string) (Sources, error) {
+	parts := strings.Split(input, ",")
+	sources := make(Sources, 0, len(parts))
+
+	for _, part := range parts {
+		source := Source(part)
+
+		if _, ok := sourceSpecificity[source]; !ok {
+			return nil, fmt.Errorf("unknown label source %q", part)
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// Set implements pflag.Value.
+func (s *Sources) Set(value string) error {
+	sources, err := ParseSources(value)
+	if err != nil {
+		return err
+	}
+
+	*s = append(*s, sources...)
+	return nil
+}
+
+// String implements pflag.Value.
+func (s Sources) String() string {
+	values := make([]string, 0, len(s))
+
+	for _, source := range s {
+		values = append(values, string(source))
+	}
+
+	return strings.Join(values, ",")
+}
+
+// Type implements pflag.Value.
+func (s Sources) Type() string {
+	return "label-source"
+}
+
+// Sets holds the labels available from each source for a single series.
+type Sets map[Source]map[string]string
+
+// Resolver resolves the values of label mappings by falling back to less
+// specific label sources when the primary source does not carry a label.
+//
+// The zero value is a Resolver without any label mappings.
+type Resolver struct {
+	mappings      Mappings
+	fallbacks     Sources
+	exposeSources bool
+}
+
+// NewResolver creates a new Resolver for the given mappings. Fallbacks
+// defines the order in which other sources are consulted if the primary
+// source of a series does not carry a mapped label. If exposeSources is true,
+// an additional `<label>_source` label is added for every mapped label which
+// contains the name of the source the value was resolved from.
+func NewResolver(mappings Mappings, fallbacks Sources, exposeSources bool) Resolver {
+	return Resolver{
+		mappings:      mappings,
+		fallbacks:     fallbacks,
+		exposeSources: exposeSources,
+	}
+}
+
+// LabelNames returns the names of the Prometheus labels.
+func (r Resolver) LabelNames() []string {
+	names := r.mappings.LabelNames()
+
+	if r.exposeSources {
+		for _, name := range r.mappings.LabelNames() {
+			names = append(names, name+"_source")
+		}
+	}
+
+	return names
+}
+
+// LabelValues resolves the values for the configured Prometheus labels. The
+// labels of the primary source are consulted first, followed by the labels of
+// the configured fallback sources which are less specific than the primary
+// one. The first non-empty value wins.
+func (r Resolver) LabelValues(primary Source, sets Sets) []string {
+	chain := r.chain(primary)
+	values := make([]string, 0, len(r.mappings))
+	sources := make([]string, 0, len(r.mappings))
+
+	for _, mapping := range r.mappings {
+		var value, source string
+
+		for _, s := range chain {
+			if v := sets[s][mapping.resourceLabelName]; v != "" {
+				value, source = v, string(s)
+				break
+			}
+		}
+
+		values = append(values, value)
+		sources = append(sources, source)
+	}
+
+	if r.exposeSources {
+		values = append(values, sources...)
+	}
+
+	return values
+}
+
+func (r Resolver) chain(primary Source) Sources {
+	chain := Sources{primary}
+
+	for _, fallback := range r.fallbacks {
+		if sourceSpecificity[fallback] > sourceSpecificity[primary] {
+			chain = append(chain, fallback)
+		}
+	}
+
+	return chain
+}
@@ -0,0 +1,94 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver(t *testing.T) {
+	mappings, err := ParseMappings("team,cost-center=cost_center")
+	assert.NoError(t, err)
+
+	sets := Sets{
+		SourceResource:  {"team": "resource-team"},
+		SourceNamespace: {"team": "namespace-team", "cost-center": "namespace-cc"},
+		SourceCluster:   {"cost-center": "cluster-cc"},
+	}
+
+	t.Run("zero value", func(t *testing.T) {
+		var resolver Resolver
+
+		assert.Empty(t, resolver.LabelNames())
+		assert.Empty(t, resolver.LabelValues(SourceResource, sets))
+	})
+
+	t.Run("without fallbacks", func(t *testing.T) {
+		resolver := NewResolver(mappings, nil, false)
+
+		assert.Equal(t, []string{"team", "cost_center"}, resolver.LabelNames())
+		assert.Equal(t, []string{"resource-team", ""}, resolver.LabelValues(SourceResource, sets))
+		assert.Equal(t, []string{"namespace-team", "namespace-cc"}, resolver.LabelValues(SourceNamespace, sets))
+	})
+
+	t.Run("with fallbacks", func(t *testing.T) {
+		resolver := NewResolver(mappings, Sources{SourceNamespace, SourceCluster}, false)
+
+		assert.Equal(t, []string{"resource-team", "namespace-cc"}, resolver.LabelValues(SourceResource, sets))
+		assert.Equal(t, []string{"namespace-team", "namespace-cc"}, resolver.LabelValues(SourceNamespace, sets))
+		assert.Equal(t, []string{"", "cluster-cc"}, resolver.LabelValues(SourceCluster, sets))
+	})
+
+	t.Run("fallback order", func(t *testing.T) {
+		resolver := NewResolver(mappings, Sources{SourceCluster, SourceNamespace}, false)
+
+		assert.Equal(t, []string{"resource-team", "cluster-cc"}, resolver.LabelValues(SourceResource, sets))
+	})
+
+	t.Run("never fall back to more specific sources", func(t *testing.T) {
+		resolver := NewResolver(mappings, Sources{SourceResource}, false)
+
+		assert.Equal(t, []string{"", "cluster-cc"}, resolver.LabelValues(SourceCluster, sets))
+		assert.Equal(t, []string{"namespace-team", "namespace-cc"}, resolver.LabelValues(SourceNamespace, sets))
+	})
+
+	t.Run("expose sources", func(t *testing.T) {
+		resolver := NewResolver(mappings, Sources{SourceNamespace, SourceCluster}, true)
+
+		assert.Equal(t, []string{"team", "cost_center", "team_source", "cost_center_source"}, resolver.LabelNames())
+		assert.Equal(
+			t,
+			[]string{"resource-team", "namespace-cc", "resource", "namespace"},
+			resolver.LabelValues(SourceResource, sets),
+		)
+		assert.Equal(
+			t,
+			[]string{"", "cluster-cc", "", "cluster"},
+			resolver.LabelValues(SourceCluster, sets),
+		)
+	})
+}
+
+func TestSources(t *testing.T) {
+	t.Run("valid input", func(t *testing.T) {
+		sources, err := ParseSources("namespace,cluster")
+		assert.NoError(t, err)
+		assert.Equal(t, Sources{SourceNamespace, SourceCluster}, sources)
+		assert.Equal(t, "namespace,cluster", sources.String())
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, input := range []string{"", "namespace,", "node"} {
+			_, err := ParseSources(input)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		var sources Sources
+
+		assert.NoError(t, sources.Set("namespace"))
+		assert.NoError(t, sources.Set("cluster"))
+		assert.Equal(t, Sources{SourceNamespace, SourceCluster}, sources)
+	})
+}